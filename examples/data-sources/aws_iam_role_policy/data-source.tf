@@ -0,0 +1,4 @@
+data "streamsec_aws_iam_role_policy" "example" {
+  real_time_events = true
+  remediation      = true
+}
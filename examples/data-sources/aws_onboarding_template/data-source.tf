@@ -0,0 +1,7 @@
+data "streamsec_aws_onboarding_template" "example" {
+  stack = "real_time_events"
+}
+
+output "template_sha256" {
+  value = data.streamsec_aws_onboarding_template.example.sha256
+}
@@ -0,0 +1,10 @@
+resource "streamsec_aws_account" "example" {
+  cloud_account_id = "123456789011"
+  display_name     = "production"
+  cloud_regions    = ["us-east-1"]
+}
+
+# Trusts the Stream.Security account of the onboarding template, set trusted_account_id to override it
+data "streamsec_aws_trust_policy" "example" {
+  external_id = streamsec_aws_account.example.external_id
+}
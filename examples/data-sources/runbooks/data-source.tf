@@ -0,0 +1,3 @@
+data "streamsec_runbooks" "aws" {
+  cloud = "aws"
+}
@@ -0,0 +1,3 @@
+ephemeral "streamsec_collection_token" "example" {
+  cloud_account_id = "123456789011"
+}
@@ -0,0 +1,3 @@
+output "is_account_id" {
+  value = provider::streamsec::is_aws_account_id("123456789011")
+}
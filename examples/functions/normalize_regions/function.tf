@@ -0,0 +1,5 @@
+resource "streamsec_aws_account" "example" {
+  cloud_account_id = "123456789011"
+  display_name     = "production"
+  cloud_regions    = provider::streamsec::normalize_regions([" US-East-1", "eu-west-1", "us-east-1"])
+}
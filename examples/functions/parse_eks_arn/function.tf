@@ -0,0 +1,7 @@
+locals {
+  cluster = provider::streamsec::parse_eks_arn("arn:aws:eks:us-east-1:123456789011:cluster/production")
+}
+
+output "cluster_name" {
+  value = local.cluster.cluster_name
+}
@@ -0,0 +1,18 @@
+data "streamsec_aws_onboarding_template" "account" {
+  stack = "account"
+}
+
+resource "streamsec_aws_account" "example" {
+  cloud_account_id = "123456789011"
+  display_name     = "production"
+  cloud_regions    = ["us-east-1"]
+}
+
+output "quick_create_url" {
+  value = provider::streamsec::template_url(
+    streamsec_aws_account.example.cloud_account_id,
+    "us-east-1",
+    streamsec_aws_account.example.external_id,
+    data.streamsec_aws_onboarding_template.account.template_url,
+  )
+}
@@ -1,6 +1,6 @@
 provider "streamsec" {
-  host = "app.streamsec.io"
-  username = "myuser"
-  password = "mypassword"
+  host         = "app.streamsec.io"
+  username     = "myuser"
+  password     = "mypassword"
   workspace_id = "123456123213213123"
 }
\ No newline at end of file
@@ -0,0 +1,4 @@
+resource "streamsec_aws_real_time_events" "example" {
+  cloud_account_id = "123456789011"
+  regions          = ["us-east-1", "eu-west-1"]
+}
@@ -0,0 +1,19 @@
+data "streamsec_runbooks" "aws" {
+  cloud = "aws"
+}
+
+resource "streamsec_aws_response_ack" "example" {
+  cloud_account_id = "123456789011"
+  region           = "us-east-1"
+  role_arn         = "arn:aws:iam::123456789011:role/StreamSecurityRemediation"
+  external_id      = "external-id"
+
+  # Leave the runbooks to streamsec_aws_remediation_runbook
+  manage_runbooks = false
+}
+
+resource "streamsec_aws_remediation_runbook" "example" {
+  cloud_account_id = streamsec_aws_response_ack.example.cloud_account_id
+  runbook          = data.streamsec_runbooks.aws.runbooks[0].id
+  role_arn         = "arn:aws:iam::123456789011:role/StreamSecurityRunbook"
+}
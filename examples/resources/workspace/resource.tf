@@ -0,0 +1,5 @@
+resource "streamsec_workspace" "example" {
+  name                = "production"
+  region              = "us-east-1"
+  data_retention_days = 90
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/machinebox/graphql"
 )
@@ -13,13 +15,37 @@ type Client struct {
 	Token         string
 	Workspace     string
 	Host          string
+
+	// SkipCredentialsValidation is set when the provider defers the login to the first request,
+	// plan-time checks then leave the API alone so plans work offline.
+	SkipCredentialsValidation bool
+
+	// credentials are kept to log in on the first request and to other workspaces on demand
+	username   *string
+	password   *string
+	mu         sync.Mutex
+	workspaces map[string]*Client
+
+	// oidc is set when the access token is exchanged from an OIDC ID token and
+	// refreshed before tokenExpiry. Tokens are obtained on first use, under tokenMu.
+	oidc        *OIDCConfig
+	tokenMu     sync.Mutex
+	tokenExpiry time.Time
+
+	limiter   *requestLimiter
+	userAgent string
 }
 
-func NewClient(host, username, password, workspace_id *string, apiToken *string) (*Client, error) {
+// NewClient creates a client without calling the API. Username/password and OIDC clients log in
+// on their first request, or when Authenticate is called.
+func NewClient(host, username, password, workspace_id *string, apiToken *string, oidc *OIDCConfig, limits Limits, userAgent string) (*Client, error) {
 
 	c := Client{
 		graphqlClient: graphql.NewClient(fmt.Sprintf("https://%s/graphql", *host)),
 		Host:          *host,
+		workspaces:    map[string]*Client{},
+		limiter:       newRequestLimiter(limits),
+		userAgent:     userAgent,
 	}
 
 	c.Token = ""
@@ -31,28 +57,111 @@ func NewClient(host, username, password, workspace_id *string, apiToken *string)
 		c.Token = *apiToken
 		return &c, nil
 	}
-	err := c.authenticate(username, password)
 
-	if err != nil {
-		return nil, err
+	if oidc != nil {
+		c.oidc = oidc
+		return &c, nil
 	}
 
+	c.username = username
+	c.password = password
+
 	return &c, nil
 }
 
-func (c *Client) authenticate(username, password *string) error {
-	data, err := c.DoRequest(`
+// Authenticate logs in now rather than on the first request, to validate the credentials.
+func (c *Client) Authenticate(ctx context.Context) error {
+	_, err := c.accessToken(ctx)
+	return err
+}
+
+// ForWorkspace returns a client for the given workspace, cached so later calls share its session.
+// Like the root client it logs in on its first request. An empty workspace or the client's own
+// workspace returns c.
+func (c *Client) ForWorkspace(workspace string) *Client {
+	if workspace == "" || workspace == c.Workspace {
+		return c
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if wc, ok := c.workspaces[workspace]; ok {
+		return wc
+	}
+
+	wc := &Client{
+		graphqlClient: c.graphqlClient,
+		Host:          c.Host,
+		Workspace:     workspace,
+		workspaces:    map[string]*Client{},
+		oidc:          c.oidc,
+		username:      c.username,
+		password:      c.password,
+		limiter:       c.limiter,
+		userAgent:     c.userAgent,
+
+		SkipCredentialsValidation: c.SkipCredentialsValidation,
+	}
+
+	if c.oidc == nil && c.username == nil {
+		// API tokens are not bound to a workspace, the customer header selects it
+		wc.Token = c.Token
+	}
+
+	c.workspaces[workspace] = wc
+
+	return wc
+}
+
+// accessToken returns the token to authenticate requests with, logging in on first use and
+// renewing an exchanged OIDC access token that is about to expire.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.Token != "" && (c.tokenExpiry.IsZero() || time.Until(c.tokenExpiry) > tokenRefreshMargin) {
+		return c.Token, nil
+	}
+
+	var err error
+	switch {
+	case c.oidc != nil:
+		err = c.exchangeOIDCToken(ctx)
+	case c.username != nil:
+		err = c.authenticate(ctx, c.username, c.password)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return c.Token, nil
+}
+
+func (c *Client) authenticate(ctx context.Context, username, password *string) error {
+	query := `
         mutation ($creds: Credentials) {
             login (credentials:$creds) {
                 access_token
             }
         }
-    `, map[string]interface{}{
+    `
+	variables := map[string]interface{}{
 		"creds": map[string]interface{}{
 			"email":    username,
-			"password": password}})
+			"password": password}}
 
-	if err != nil {
+	req := graphql.NewRequest(query)
+	if c.Workspace != "" {
+		req.Header.Set("customer", c.Workspace)
+	}
+	for key, value := range variables {
+		req.Var(key, value)
+	}
+
+	var data map[string]interface{}
+	if err := c.run(ctx, req, query, variables, &data); err != nil {
 		return err
 	}
 
@@ -67,11 +176,16 @@ func (c *Client) authenticate(username, password *string) error {
 	return nil
 }
 
-func (c *Client) DoRequest(query string, variables map[string]interface{}) (map[string]interface{}, error) {
+func (c *Client) DoRequest(ctx context.Context, query string, variables map[string]interface{}) (map[string]interface{}, error) {
 	req := graphql.NewRequest(query)
 
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	if c.Workspace != "" {
 		req.Header.Set("customer", c.Workspace)
@@ -81,19 +195,16 @@ func (c *Client) DoRequest(query string, variables map[string]interface{}) (map[
 		req.Var(key, value)
 	}
 
-	// define a Context for the request
-	ctx := context.Background()
-
 	// run it and capture the response
 	var data map[string]interface{}
-	if err := c.graphqlClient.Run(ctx, req, &data); err != nil {
+	if err := c.run(ctx, req, query, variables, &data); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
 
-func (c *Client) DoRequestWithToken(query string, variables map[string]interface{}, authToken string) (map[string]interface{}, error) {
+func (c *Client) DoRequestWithToken(ctx context.Context, query string, variables map[string]interface{}, authToken string) (map[string]interface{}, error) {
 	req := graphql.NewRequest(query)
 
 	req.Header.Set("Authorization", "Bearer "+authToken)
@@ -102,14 +213,33 @@ func (c *Client) DoRequestWithToken(query string, variables map[string]interface
 		req.Var(key, value)
 	}
 
-	// define a Context for the request
-	ctx := context.Background()
-
 	// run it and capture the response
 	var data map[string]interface{}
-	if err := c.graphqlClient.Run(ctx, req, &data); err != nil {
+	if err := c.run(ctx, req, query, variables, &data); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
+
+// run sends a GraphQL request, applying the client's rate and concurrency limits, and logs it.
+// Errors carry the request ID.
+func (c *Client) run(ctx context.Context, req *graphql.Request, query string, variables map[string]interface{}, resp interface{}) error {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	requestID := c.setRequestHeaders(req.Header)
+
+	start := time.Now()
+	err = c.graphqlClient.Run(ctx, req, resp)
+	c.logGraphQLRequest(c.logContext(ctx), requestID, query, variables, start, err)
+
+	if err != nil {
+		return &RequestError{RequestID: requestID, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CredentialsCheckResult is the response of the Stream.Security connection check.
+type CredentialsCheckResult struct {
+	Valid              bool     `json:"valid"`
+	Message            string   `json:"message"`
+	MissingPermissions []string `json:"missing_permissions"`
+}
+
+// Summary describes why the check failed, including any missing permissions or scopes.
+func (r *CredentialsCheckResult) Summary() string {
+	summary := r.Message
+	if summary == "" {
+		summary = "The credentials were rejected."
+	}
+	if len(r.MissingPermissions) > 0 {
+		summary += "\nMissing permissions: " + strings.Join(r.MissingPermissions, ", ")
+	}
+	return summary
+}
+
+// ValidateCredentials runs the connection check for the given cloud ("azure" or "gcp") without
+// storing the credentials, so they can be verified before replacing the active ones.
+func (c *Client) ValidateCredentials(ctx context.Context, cloud string, accountToken string, body interface{}) (*CredentialsCheckResult, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://%s/%s/validate-credentials", c.Host, cloud)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accountToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Only a 200 carries a check result, anything else is a server error and not a verdict on
+	// the credentials
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("connection check failed: %w", c.ResponseError(resp))
+	}
+
+	var result CredentialsCheckResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("unable to decode connection check response: %w", err)
+	}
+
+	return &result, nil
+}
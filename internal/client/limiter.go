@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limits throttles the requests sent to the Stream.Security API. Zero values disable the
+// corresponding limit.
+type Limits struct {
+	RequestsPerSecond     float64
+	MaxConcurrentRequests int64
+}
+
+// requestLimiter is shared by a client and its workspace clients, as they all call the same API.
+type requestLimiter struct {
+	rate     *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newRequestLimiter(limits Limits) *requestLimiter {
+	l := &requestLimiter{}
+	if limits.RequestsPerSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), int(math.Max(1, math.Ceil(limits.RequestsPerSecond))))
+	}
+	if limits.MaxConcurrentRequests > 0 {
+		l.inFlight = make(chan struct{}, limits.MaxConcurrentRequests)
+	}
+	return l
+}
+
+// acquire waits for a rate limit token and an in-flight slot. The returned function releases
+// the slot once the request is done.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Do sends a REST request to the Stream.Security API, applying the client's rate and
+// concurrency limits, and logs it. Use ResponseError to report an unexpected status.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	requestID := c.setRequestHeaders(req.Header)
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	c.logRESTRequest(c.logContext(req.Context()), requestID, req, resp, start, err)
+
+	if err != nil {
+		return nil, &RequestError{RequestID: requestID, Err: err}
+	}
+
+	return resp, nil
+}
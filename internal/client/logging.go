@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem of the API client, enabled with TF_LOG_PROVIDER_STREAMSEC_CLIENT.
+const logSubsystem = "streamsec_client"
+
+// sensitiveFieldKeys are the log fields and request body keys whose values are never logged.
+var sensitiveFieldKeys = []string{
+	"authorization",
+	"x-lightlytics-token",
+	"password",
+	"token",
+	"access_token",
+	"api_token",
+	"account_token",
+	"account_auth_token",
+	"lightlytics_collection_token",
+	"streamsec_collection_token",
+	"client_secret",
+	"private_key",
+	"private_key_id",
+}
+
+var operationRegex = regexp.MustCompile(`^\s*(query|mutation)\b[^{]*\{\s*(\w+)`)
+
+// logContext returns ctx with the client subsystem, masking the sensitive fields.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem)
+	return tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, logSubsystem, sensitiveFieldKeys...)
+}
+
+// operationName returns the kind and first field of a GraphQL query, e.g. "query accounts".
+func operationName(query string) string {
+	match := operationRegex.FindStringSubmatch(query)
+	if match == nil {
+		return "query"
+	}
+	return match[1] + " " + match[2]
+}
+
+// redact returns a copy of a decoded JSON value with the values of sensitive keys masked.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				redacted[key] = "***"
+			} else {
+				redacted[key] = redact(item)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redact(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveFieldKeys {
+		if key == sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedJSON encodes the redacted form of a request body or variables for logging.
+func redactedJSON(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return ""
+	}
+	redacted, err := json.Marshal(redact(decoded))
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+// logGraphQLRequest logs a GraphQL call once it completed.
+func (c *Client) logGraphQLRequest(ctx context.Context, requestID string, query string, variables map[string]interface{}, start time.Time, err error) {
+	fields := map[string]interface{}{
+		"operation":   operationName(query),
+		"workspace":   c.Workspace,
+		"request_id":  requestID,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"body":        redactedJSON(variables),
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "GraphQL request failed", fields)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, logSubsystem, "GraphQL request", fields)
+}
+
+// logRESTRequest logs a REST call once it completed.
+func (c *Client) logRESTRequest(ctx context.Context, requestID string, req *http.Request, resp *http.Response, start time.Time, err error) {
+	fields := map[string]interface{}{
+		"operation":   req.Method + " " + req.URL.Path,
+		"workspace":   c.Workspace,
+		"request_id":  requestID,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			raw, _ := io.ReadAll(body)
+			body.Close()
+			var decoded interface{}
+			if json.Unmarshal(raw, &decoded) == nil {
+				fields["body"] = redactedJSON(decoded)
+			}
+		}
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "REST request failed", fields)
+		return
+	}
+
+	fields["status"] = resp.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "REST request", fields)
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/machinebox/graphql"
+)
+
+// tokenRefreshMargin is how long before expiry an exchanged access token is renewed.
+const tokenRefreshMargin = time.Minute
+
+// OIDCConfig describes where to get the OIDC ID token that is exchanged for a Stream.Security
+// access token. The first source that is set is used:
+//   - Token, the JWT itself (e.g. a GitLab CI id_tokens variable)
+//   - TokenFilePath, a file holding the JWT, re-read on every refresh
+//   - RequestURL and RequestToken, the GitHub Actions ID token endpoint
+//     (ACTIONS_ID_TOKEN_REQUEST_URL and ACTIONS_ID_TOKEN_REQUEST_TOKEN)
+type OIDCConfig struct {
+	Token         string
+	TokenFilePath string
+	RequestURL    string
+	RequestToken  string
+	Audience      string
+}
+
+// idToken returns a fresh OIDC ID token from the configured source.
+func (o *OIDCConfig) idToken(ctx context.Context) (string, error) {
+	if o.Token != "" {
+		return o.Token, nil
+	}
+
+	if o.TokenFilePath != "" {
+		token, err := os.ReadFile(o.TokenFilePath)
+		if err != nil {
+			return "", fmt.Errorf("unable to read OIDC token file: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	if o.RequestURL == "" || o.RequestToken == "" {
+		return "", errors.New("no OIDC token source, set an OIDC token, token file or request URL and token")
+	}
+
+	requestURL, err := url.Parse(o.RequestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid OIDC request URL: %w", err)
+	}
+	if o.Audience != "" {
+		query := requestURL.Query()
+		query.Set("audience", o.Audience)
+		requestURL.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.RequestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to request OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("unable to request OIDC token: %s", resp.Status)
+	}
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("unable to decode OIDC token response: %w", err)
+	}
+	if result.Value == "" {
+		return "", errors.New("the OIDC token response is empty")
+	}
+
+	return result.Value, nil
+}
+
+// exchangeOIDCToken trades a fresh OIDC ID token for a Stream.Security access token.
+func (c *Client) exchangeOIDCToken(ctx context.Context) error {
+	idToken, err := c.oidc.idToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        mutation ($token: String!) {
+            oidcLogin (token:$token) {
+                access_token
+                expires_in
+            }
+        }
+    `
+	variables := map[string]interface{}{
+		"token": idToken,
+	}
+
+	req := graphql.NewRequest(query)
+	if c.Workspace != "" {
+		req.Header.Set("customer", c.Workspace)
+	}
+	req.Var("token", idToken)
+
+	var data map[string]interface{}
+	if err := c.run(ctx, req, query, variables, &data); err != nil {
+		return fmt.Errorf("unable to exchange OIDC token: %w", err)
+	}
+
+	login, _ := data["oidcLogin"].(map[string]interface{})
+	token, valid := login["access_token"].(string)
+
+	if !valid {
+		return errors.New("unable to exchange OIDC token: no access token returned")
+	}
+
+	c.Token = token
+	c.tokenExpiry = time.Time{}
+	if expiresIn, ok := login["expires_in"].(float64); ok {
+		c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+// requestIDHeader correlates a call with the Stream.Security API logs.
+const requestIDHeader = "X-Request-ID"
+
+// RequestError is a failed API call, carrying the request ID to quote in support tickets.
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%s (request ID: %s)", e.Err, e.RequestID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseError describes an unexpected REST response status, including its request ID.
+func (c *Client) ResponseError(resp *http.Response) error {
+	return &RequestError{
+		RequestID: resp.Request.Header.Get(requestIDHeader),
+		Err:       errors.New(resp.Status),
+	}
+}
+
+func newRequestID() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// setRequestHeaders sets the User-Agent and a new request ID, returning the ID.
+func (c *Client) setRequestHeaders(header http.Header) string {
+	requestID := newRequestID()
+	header.Set(requestIDHeader, requestID)
+	if c.userAgent != "" {
+		header.Set("User-Agent", c.userAgent)
+	}
+	return requestID
+}
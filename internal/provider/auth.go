@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// authMode is the way the provider authenticates to the Stream.Security API.
+type authMode int
+
+const (
+	authModeNone authMode = iota
+	authModeAPIToken
+	authModePassword
+	authModeOIDC
+)
+
+// authCredentials are the credentials read from one configuration source.
+type authCredentials struct {
+	source   string
+	apiToken string
+	username string
+	password string
+}
+
+func (c authCredentials) isSet() bool {
+	return c.apiToken != "" || c.username != "" || c.password != ""
+}
+
+// firstSet returns the index of the first source where value returns a non-empty string, or -1.
+func firstSet(sources []authCredentials, value func(authCredentials) string) int {
+	for i, source := range sources {
+		if value(source) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveAuth merges the credentials of the sources, ordered by precedence, attribute by
+// attribute, so e.g. a username from the environment combines with a password from the profile.
+// The configuration sets both or neither, see ConfigValidators. When both an API token and a
+// username/password are set the one from the higher precedence source is used. The merged
+// credentials are validated adding one diagnostic per misconfiguration.
+func resolveAuth(sources []authCredentials, useOIDC bool, workspaceId string, diags *diag.Diagnostics) (authCredentials, authMode) {
+	if useOIDC {
+		for _, source := range sources {
+			if source.source == "configuration" && source.isSet() {
+				diags.AddAttributeError(
+					path.Root("use_oidc"),
+					"Conflicting Stream.Security Credentials",
+					"The provider cannot create the Stream.Security API client as use_oidc is set together with api_token or username/password in the configuration. "+
+						"Remove either use_oidc or the other credentials.",
+				)
+				return authCredentials{}, authModeNone
+			}
+		}
+		return authCredentials{source: "OIDC"}, authModeOIDC
+	}
+
+	tokenIndex := firstSet(sources, func(c authCredentials) string { return c.apiToken })
+	usernameIndex := firstSet(sources, func(c authCredentials) string { return c.username })
+	passwordIndex := firstSet(sources, func(c authCredentials) string { return c.password })
+
+	// the index of the first source setting a username or a password
+	passwordAuthIndex := usernameIndex
+	if passwordAuthIndex == -1 || (passwordIndex != -1 && passwordIndex < passwordAuthIndex) {
+		passwordAuthIndex = passwordIndex
+	}
+
+	switch {
+	case tokenIndex == -1 && passwordAuthIndex == -1:
+		diags.AddAttributeError(
+			path.Root("api_token"),
+			"Missing Stream.Security Credentials",
+			"The provider cannot create the Stream.Security API client as there are no Stream.Security credentials. "+
+				"Set api_token, username and password with workspace_id, or use_oidc in the configuration, "+
+				"the STREAMSEC_* environment variables or a credentials file profile.",
+		)
+		return authCredentials{}, authModeNone
+	case tokenIndex != -1 && tokenIndex == passwordAuthIndex:
+		diags.AddAttributeError(
+			path.Root("api_token"),
+			"Conflicting Stream.Security Credentials",
+			fmt.Sprintf("The provider cannot create the Stream.Security API client as the %s sets both an API token and a username/password. "+
+				"Set only one of them.", sources[tokenIndex].source),
+		)
+		return authCredentials{}, authModeNone
+	case tokenIndex != -1 && (passwordAuthIndex == -1 || tokenIndex < passwordAuthIndex):
+		return authCredentials{source: sources[tokenIndex].source, apiToken: sources[tokenIndex].apiToken}, authModeAPIToken
+	}
+
+	creds := authCredentials{source: sources[passwordAuthIndex].source}
+	mode := authModePassword
+	if usernameIndex == -1 {
+		diags.AddAttributeError(
+			path.Root("username"),
+			"Missing Stream.Security API Username",
+			"The provider cannot create the Stream.Security API client as a password is set without a username. "+
+				"Set the username value in the configuration, use the STREAMSEC_USERNAME environment variable or set it in the credentials file profile.",
+		)
+		mode = authModeNone
+	} else {
+		creds.username = sources[usernameIndex].username
+	}
+	if passwordIndex == -1 {
+		diags.AddAttributeError(
+			path.Root("password"),
+			"Missing Stream.Security API Password",
+			"The provider cannot create the Stream.Security API client as a username is set without a password. "+
+				"Set the password value in the configuration, use the STREAMSEC_PASSWORD environment variable or set it in the credentials file profile.",
+		)
+		mode = authModeNone
+	} else {
+		creds.password = sources[passwordIndex].password
+	}
+	if workspaceId == "" {
+		diags.AddAttributeError(
+			path.Root("workspace_id"),
+			"Missing Stream.Security API Workspace ID",
+			"The provider cannot create the Stream.Security API client as username/password authentication requires a workspace ID. "+
+				"Set the workspace_id value in the configuration or use the STREAMSEC_WORKSPACE_ID environment variable.",
+		)
+		mode = authModeNone
+	}
+
+	return creds, mode
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestResolveAuth(t *testing.T) {
+	config := func(apiToken, username, password string) authCredentials {
+		return authCredentials{source: "configuration", apiToken: apiToken, username: username, password: password}
+	}
+	env := func(apiToken, username, password string) authCredentials {
+		return authCredentials{source: "STREAMSEC_* environment", apiToken: apiToken, username: username, password: password}
+	}
+	profile := func(apiToken, username, password string) authCredentials {
+		return authCredentials{source: `profile "default" of the credentials file`, apiToken: apiToken, username: username, password: password}
+	}
+
+	cases := []struct {
+		name        string
+		sources     []authCredentials
+		useOIDC     bool
+		workspaceId string
+		wantMode    authMode
+		wantCreds   authCredentials
+		wantErrors  []path.Path
+	}{
+		{
+			name:       "no credentials",
+			sources:    []authCredentials{config("", "", ""), env("", "", ""), profile("", "", "")},
+			wantMode:   authModeNone,
+			wantErrors: []path.Path{path.Root("api_token")},
+		},
+		{
+			name:      "api token in configuration",
+			sources:   []authCredentials{config("token", "", ""), env("", "", ""), profile("", "", "")},
+			wantMode:  authModeAPIToken,
+			wantCreds: authCredentials{source: "configuration", apiToken: "token"},
+		},
+		{
+			name:      "api token in profile",
+			sources:   []authCredentials{config("", "", ""), env("", "", ""), profile("token", "", "")},
+			wantMode:  authModeAPIToken,
+			wantCreds: authCredentials{source: `profile "default" of the credentials file`, apiToken: "token"},
+		},
+		{
+			name:        "username and password in configuration",
+			sources:     []authCredentials{config("", "user", "pass"), env("", "", ""), profile("", "", "")},
+			workspaceId: "workspace",
+			wantMode:    authModePassword,
+			wantCreds:   authCredentials{source: "configuration", username: "user", password: "pass"},
+		},
+		{
+			name:        "username in environment and password in profile",
+			sources:     []authCredentials{config("", "", ""), env("", "user", ""), profile("", "", "pass")},
+			workspaceId: "workspace",
+			wantMode:    authModePassword,
+			wantCreds:   authCredentials{source: "STREAMSEC_* environment", username: "user", password: "pass"},
+		},
+		{
+			name:        "password in environment and username in profile",
+			sources:     []authCredentials{config("", "", ""), env("", "", "pass"), profile("", "user", "other")},
+			workspaceId: "workspace",
+			wantMode:    authModePassword,
+			wantCreds:   authCredentials{source: "STREAMSEC_* environment", username: "user", password: "pass"},
+		},
+		{
+			name:       "username without password",
+			sources:    []authCredentials{config("", "", ""), env("", "user", ""), profile("", "", "")},
+			wantMode:   authModeNone,
+			wantErrors: []path.Path{path.Root("password"), path.Root("workspace_id")},
+		},
+		{
+			name:        "password without username",
+			sources:     []authCredentials{config("", "", ""), env("", "", "pass"), profile("", "", "")},
+			workspaceId: "workspace",
+			wantMode:    authModeNone,
+			wantErrors:  []path.Path{path.Root("username")},
+		},
+		{
+			name:       "username and password without workspace",
+			sources:    []authCredentials{config("", "user", "pass"), env("", "", ""), profile("", "", "")},
+			wantMode:   authModeNone,
+			wantErrors: []path.Path{path.Root("workspace_id")},
+		},
+		{
+			name:       "api token and password in the same source",
+			sources:    []authCredentials{config("", "", ""), env("token", "user", "pass"), profile("", "", "")},
+			wantMode:   authModeNone,
+			wantErrors: []path.Path{path.Root("api_token")},
+		},
+		{
+			name:      "api token in configuration overrides username and password in environment",
+			sources:   []authCredentials{config("token", "", ""), env("", "user", "pass"), profile("", "", "")},
+			wantMode:  authModeAPIToken,
+			wantCreds: authCredentials{source: "configuration", apiToken: "token"},
+		},
+		{
+			name:        "username and password in configuration override api token in profile",
+			sources:     []authCredentials{config("", "user", "pass"), env("", "", ""), profile("token", "", "")},
+			workspaceId: "workspace",
+			wantMode:    authModePassword,
+			wantCreds:   authCredentials{source: "configuration", username: "user", password: "pass"},
+		},
+		{
+			name:      "oidc",
+			sources:   []authCredentials{config("", "", ""), env("token", "", ""), profile("", "", "")},
+			useOIDC:   true,
+			wantMode:  authModeOIDC,
+			wantCreds: authCredentials{source: "OIDC"},
+		},
+		{
+			name:       "oidc with credentials in configuration",
+			sources:    []authCredentials{config("token", "", ""), env("", "", ""), profile("", "", "")},
+			useOIDC:    true,
+			wantMode:   authModeNone,
+			wantErrors: []path.Path{path.Root("use_oidc")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+
+			creds, mode := resolveAuth(tc.sources, tc.useOIDC, tc.workspaceId, &diags)
+
+			if mode != tc.wantMode {
+				t.Errorf("resolveAuth() mode = %v, want %v", mode, tc.wantMode)
+			}
+			if len(tc.wantErrors) == 0 && creds != tc.wantCreds {
+				t.Errorf("resolveAuth() credentials = %+v, want %+v", creds, tc.wantCreds)
+			}
+
+			errors := diags.Errors()
+			if len(errors) != len(tc.wantErrors) {
+				t.Fatalf("resolveAuth() got %d errors, want %d: %v", len(errors), len(tc.wantErrors), errors)
+			}
+			for i, err := range errors {
+				withPath, ok := err.(diag.DiagnosticWithPath)
+				if !ok || !withPath.Path().Equal(tc.wantErrors[i]) {
+					t.Errorf("resolveAuth() error %d = %v, want an error on %s", i, err, tc.wantErrors[i])
+				}
+			}
+		})
+	}
+}
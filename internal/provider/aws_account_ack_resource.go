@@ -3,8 +3,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -21,6 +21,13 @@ import (
 var _ resource.Resource = &AWSAccountAckResource{}
 var _ resource.ResourceWithImportState = &AWSAccountAckResource{}
 
+// accountAckLostStatuses are the account statuses reported when the acknowledged role no longer
+// works, e.g. because it was deleted or its trust policy changed. Transitional statuses such as
+// PENDING, reported while a new acknowledgement is processed, are not lost.
+var accountAckLostStatuses = map[string]bool{
+	"UNAUTHORIZED": true,
+}
+
 func NewAWSAccountAckResource() resource.Resource {
 	return &AWSAccountAckResource{}
 }
@@ -33,6 +40,7 @@ type AWSAccountAckResourceModel struct {
 	RoleARN        types.String `tfsdk:"role_arn"`
 	CloudAccountID types.String `tfsdk:"cloud_account_id"`
 	StackRegion    types.String `tfsdk:"stack_region"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AWSAccountAckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -64,13 +72,20 @@ func (r *AWSAccountAckResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 				// add validator to check if the cloud_account_id is a valid AWS account ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^(\d{12})$`), "The cloud account ID must be a 12-digit number."),
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
 				},
 			},
 			"stack_region": schema.StringAttribute{
 				Description: "The stack region.",
 				Required:    true,
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -104,6 +119,8 @@ func (r *AWSAccountAckResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -120,7 +137,7 @@ func (r *AWSAccountAckResource) Create(ctx context.Context, req resource.CreateR
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -146,9 +163,6 @@ func (r *AWSAccountAckResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Account found: %v", data))
-	tflog.Info(ctx, fmt.Sprintf("Account auth token: %v", account_auth_token))
-
 	query = `
 		mutation AccountAcknowledge($input: AccountAckInput){
         accountAcknowledge(account: $input)
@@ -167,17 +181,13 @@ func (r *AWSAccountAckResource) Create(ctx context.Context, req resource.CreateR
 		},
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err = r.client.DoRequestWithToken(query, variables, account_auth_token)
+	res, err = client.DoRequestWithToken(ctx, query, variables, account_auth_token)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send account acknowledge, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -196,6 +206,8 @@ func (r *AWSAccountAckResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -210,10 +222,11 @@ func (r *AWSAccountAckResource) Read(ctx context.Context, req resource.ReadReque
 				lightlytics_collection_token
 				account_auth_token
 				role_arn
+				status
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -227,11 +240,21 @@ func (r *AWSAccountAckResource) Read(ctx context.Context, req resource.ReadReque
 
 		account := acc.(map[string]interface{})
 		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
+			status, _ := account["status"].(string)
+			roleARN, _ := account["role_arn"].(string)
+			if accountAckLostStatuses[status] {
+				resp.Diagnostics.AddWarning(
+					"Account acknowledgement lost",
+					fmt.Sprintf("Stream.Security can no longer use the role of account %s (status %s), the acknowledgement will be recreated.", data.CloudAccountID.ValueString(), status),
+				)
+				resp.State.RemoveResource(ctx)
+				return
+			}
 			data.ID = types.StringValue(account["_id"].(string))
 			data.CloudAccountID = types.StringValue(account["cloud_account_id"].(string))
 			data.StackRegion = types.StringValue(account["stack_region"].(string))
-			data.RoleARN = types.StringValue("")
-			if roleARN, ok := account["role_arn"].(string); ok {
+			// The role is only reported once the acknowledgement is processed, keep the acknowledged one until then
+			if roleARN != "" {
 				data.RoleARN = types.StringValue(roleARN)
 			}
 			accountFound = true
@@ -258,6 +281,8 @@ func (r *AWSAccountAckResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -274,7 +299,7 @@ func (r *AWSAccountAckResource) Update(ctx context.Context, req resource.UpdateR
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -300,9 +325,6 @@ func (r *AWSAccountAckResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Account found: %v", data))
-	tflog.Info(ctx, fmt.Sprintf("Account auth token: %v", account_auth_token))
-
 	// check if there was a change in display_name
 	if data.RoleARN != state.RoleARN {
 		query := `
@@ -319,9 +341,7 @@ func (r *AWSAccountAckResource) Update(ctx context.Context, req resource.UpdateR
 			},
 		}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequestWithToken(query, variables, account_auth_token)
+		_, err := client.DoRequestWithToken(ctx, query, variables, account_auth_token)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -343,6 +363,63 @@ func (r *AWSAccountAckResource) Delete(ctx context.Context, req resource.DeleteR
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	query := `
+		query {
+			accounts {
+				_id
+				cloud_account_id
+				account_auth_token
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	accounts := res["accounts"].([]interface{})
+	accountFound := false
+	account_auth_token := ""
+
+	for _, acc := range accounts {
+
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
+			data.ID = types.StringValue(account["_id"].(string))
+			account_auth_token, accountFound = account["account_auth_token"].(string)
+		}
+	}
+
+	// The account was already removed or never got an auth token, nothing to un-acknowledge
+	if !accountFound {
+		return
+	}
+
+	// Reset the account to pending so Stream.Security stops using the role
+	query = `
+		mutation AccountUnacknowledge($account: AccountUnackInput) {
+			accountUnacknowledge(account: $account)
+		}`
+
+	variables := map[string]interface{}{
+		"account": map[string]interface{}{
+			"lightlytics_internal_account_id": data.ID.ValueString(),
+			"account_type":                    "AWS",
+			"cloud_account_id":                data.CloudAccountID.ValueString(),
+		},
+	}
+
+	_, err = client.DoRequestWithToken(ctx, query, variables, account_auth_token)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send account un-acknowledge, got error: %s", err))
+		return
+	}
 }
 
 func (r *AWSAccountAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -29,14 +29,40 @@ type AWSAccountDataSource struct {
 
 // AWSAccountDataSourceModel describes the data source data model.
 type AWSAccountDataSourceModel struct {
-	ID                       types.String `tfsdk:"id"`
-	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
-	DisplayName              types.String `tfsdk:"display_name"`
-	CloudRegions             types.List   `tfsdk:"cloud_regions"`
-	TemplateURL              types.String `tfsdk:"template_url"`
-	ExternalID               types.String `tfsdk:"external_id"`
-	StreamSecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
-	AccountAuthToken         types.String `tfsdk:"account_auth_token"`
+	ID                       types.String                `tfsdk:"id"`
+	CloudAccountID           types.String                `tfsdk:"cloud_account_id"`
+	DisplayName              types.String                `tfsdk:"display_name"`
+	CloudRegions             types.List                  `tfsdk:"cloud_regions"`
+	TemplateURL              types.String                `tfsdk:"template_url"`
+	ExternalID               types.String                `tfsdk:"external_id"`
+	StreamSecCollectionToken types.String                `tfsdk:"streamsec_collection_token"`
+	AccountAuthToken         types.String                `tfsdk:"account_auth_token"`
+	Status                   types.String                `tfsdk:"status"`
+	StackRegion              types.String                `tfsdk:"stack_region"`
+	RoleARN                  types.String                `tfsdk:"role_arn"`
+	RealtimeRegions          types.List                  `tfsdk:"realtime_regions"`
+	Remediation              *AWSAccountRemediationModel `tfsdk:"remediation"`
+	Cost                     *AWSAccountCostModel        `tfsdk:"cost"`
+	CreationDate             types.String                `tfsdk:"creation_date"`
+	LastSyncDate             types.String                `tfsdk:"last_sync_date"`
+	WorkspaceID              types.String                `tfsdk:"workspace_id"`
+}
+
+// AWSAccountRemediationModel describes the remediation settings of the account.
+type AWSAccountRemediationModel struct {
+	Status          types.String `tfsdk:"status"`
+	RoleARN         types.String `tfsdk:"role_arn"`
+	StackID         types.String `tfsdk:"stack_id"`
+	RunbookList     types.List   `tfsdk:"runbook_list"`
+	RunbookRoleList types.List   `tfsdk:"runbook_role_list"`
+}
+
+// AWSAccountCostModel describes the cost collection settings of the account.
+type AWSAccountCostModel struct {
+	Status    types.String `tfsdk:"status"`
+	RoleARN   types.String `tfsdk:"role_arn"`
+	BucketARN types.String `tfsdk:"bucket_arn"`
+	CURPrefix types.String `tfsdk:"cur_prefix"`
 }
 
 func (d *AWSAccountDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -77,10 +103,91 @@ func (d *AWSAccountDataSource) Schema(ctx context.Context, req datasource.Schema
 			"streamsec_collection_token": schema.StringAttribute{
 				MarkdownDescription: "The Stream Security collection token of the account.",
 				Computed:            true,
+				Sensitive:           true,
 			},
 			"account_auth_token": schema.StringAttribute{
 				MarkdownDescription: "The account auth token of the account.",
 				Computed:            true,
+				Sensitive:           true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The onboarding status of the account.",
+				Computed:            true,
+			},
+			"stack_region": schema.StringAttribute{
+				MarkdownDescription: "The region of the onboarding CloudFormation stack.",
+				Computed:            true,
+			},
+			"role_arn": schema.StringAttribute{
+				MarkdownDescription: "The ARN of the IAM role acknowledged for the account.",
+				Computed:            true,
+			},
+			"realtime_regions": schema.ListAttribute{
+				MarkdownDescription: "The regions with real-time events collection.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"remediation": schema.SingleNestedAttribute{
+				MarkdownDescription: "The remediation settings of the account, null when remediation was never enabled.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						MarkdownDescription: "The remediation status.",
+						Computed:            true,
+					},
+					"role_arn": schema.StringAttribute{
+						MarkdownDescription: "The ARN of the remediation IAM role.",
+						Computed:            true,
+					},
+					"stack_id": schema.StringAttribute{
+						MarkdownDescription: "The ID of the remediation stack.",
+						Computed:            true,
+					},
+					"runbook_list": schema.ListAttribute{
+						MarkdownDescription: "The enabled runbooks.",
+						Computed:            true,
+						ElementType:         types.StringType,
+					},
+					"runbook_role_list": schema.ListAttribute{
+						MarkdownDescription: "The roles the runbooks run with.",
+						Computed:            true,
+						ElementType:         types.StringType,
+					},
+				},
+			},
+			"cost": schema.SingleNestedAttribute{
+				MarkdownDescription: "The cost collection settings of the account, null when cost collection was never enabled.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						MarkdownDescription: "The cost collection status.",
+						Computed:            true,
+					},
+					"role_arn": schema.StringAttribute{
+						MarkdownDescription: "The ARN of the cost IAM role.",
+						Computed:            true,
+					},
+					"bucket_arn": schema.StringAttribute{
+						MarkdownDescription: "The ARN of the bucket holding the cost and usage reports.",
+						Computed:            true,
+					},
+					"cur_prefix": schema.StringAttribute{
+						MarkdownDescription: "The prefix of the cost and usage reports in the bucket.",
+						Computed:            true,
+					},
+				},
+			},
+			"creation_date": schema.StringAttribute{
+				MarkdownDescription: "When the account was created.",
+				Computed:            true,
+			},
+			"last_sync_date": schema.StringAttribute{
+				MarkdownDescription: "When the account was last synced.",
+				Computed:            true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
 			},
 		},
 	}
@@ -116,6 +223,8 @@ func (d *AWSAccountDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	client := workspaceClient(d.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -129,10 +238,30 @@ func (d *AWSAccountDataSource) Read(ctx context.Context, req datasource.ReadRequ
 				lightlytics_collection_token
 				account_auth_token
 				status
+				stack_region
+				role_arn
+				creation_date
+				last_sync_date
+				realtime_regions {
+					region_name
+				}
+				remediation {
+					status
+					role_arn
+					stack_id
+					runbook_list
+					runbook_role_list
+				}
+				cost {
+					status
+					role_arn
+					bucket_arn
+					cur_prefix
+				}
 			}
 		}`
 
-	res, err := d.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -153,10 +282,44 @@ func (d *AWSAccountDataSource) Read(ctx context.Context, req datasource.ReadRequ
 			data.ID = types.StringValue(account["_id"].(string))
 			data.DisplayName = types.StringValue(account["display_name"].(string))
 			data.CloudRegions = utils.ConvertInterfaceToTypesList(account["cloud_regions"].([]interface{}))
-			data.TemplateURL = types.StringValue(account["template_url"].(string))
-			data.ExternalID = types.StringValue(account["external_id"].(string))
-			data.StreamSecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			data.AccountAuthToken = types.StringValue(account["account_auth_token"].(string))
+			data.TemplateURL = utils.ConvertInterfaceToTypesString(account["template_url"])
+			data.ExternalID = utils.ConvertInterfaceToTypesString(account["external_id"])
+			data.StreamSecCollectionToken = utils.ConvertInterfaceToTypesString(account["lightlytics_collection_token"])
+			data.AccountAuthToken = utils.ConvertInterfaceToTypesString(account["account_auth_token"])
+			data.Status = utils.ConvertInterfaceToTypesString(account["status"])
+			data.StackRegion = utils.ConvertInterfaceToTypesString(account["stack_region"])
+			data.RoleARN = utils.ConvertInterfaceToTypesString(account["role_arn"])
+			data.CreationDate = utils.ConvertInterfaceToTypesString(account["creation_date"])
+			data.LastSyncDate = utils.ConvertInterfaceToTypesString(account["last_sync_date"])
+
+			realtimeRegions := []interface{}{}
+			if regions, ok := account["realtime_regions"].([]interface{}); ok {
+				for _, region := range regions {
+					realtimeRegions = append(realtimeRegions, region.(map[string]interface{})["region_name"])
+				}
+			}
+			data.RealtimeRegions = utils.ConvertInterfaceToTypesList(realtimeRegions)
+
+			data.Remediation = nil
+			if remediation, ok := account["remediation"].(map[string]interface{}); ok {
+				data.Remediation = &AWSAccountRemediationModel{
+					Status:          utils.ConvertInterfaceToTypesString(remediation["status"]),
+					RoleARN:         utils.ConvertInterfaceToTypesString(remediation["role_arn"]),
+					StackID:         utils.ConvertInterfaceToTypesString(remediation["stack_id"]),
+					RunbookList:     utils.ConvertOptionalInterfaceToTypesList(remediation["runbook_list"]),
+					RunbookRoleList: utils.ConvertOptionalInterfaceToTypesList(remediation["runbook_role_list"]),
+				}
+			}
+
+			data.Cost = nil
+			if cost, ok := account["cost"].(map[string]interface{}); ok {
+				data.Cost = &AWSAccountCostModel{
+					Status:    utils.ConvertInterfaceToTypesString(cost["status"]),
+					RoleARN:   utils.ConvertInterfaceToTypesString(cost["role_arn"]),
+					BucketARN: utils.ConvertInterfaceToTypesString(cost["bucket_arn"]),
+					CURPrefix: utils.ConvertInterfaceToTypesString(cost["cur_prefix"]),
+				}
+			}
 			accountFound = true
 		}
 	}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
 	"terraform-provider-streamsec/internal/utils"
 
@@ -38,6 +37,7 @@ type AWSAccountResourceModel struct {
 	ExternalID               types.String `tfsdk:"external_id"`
 	StreamSecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
 	AccountAuthToken         types.String `tfsdk:"account_auth_token"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AWSAccountResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -69,7 +69,7 @@ func (r *AWSAccountResource) Schema(ctx context.Context, req resource.SchemaRequ
 				},
 				// add validator to check if the cloud_account_id is a valid AWS account ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^(\d{12})$`), "The cloud account ID must be a 12-digit number."),
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
 				},
 			},
 			"cloud_regions": schema.ListAttribute{
@@ -94,6 +94,7 @@ func (r *AWSAccountResource) Schema(ctx context.Context, req resource.SchemaRequ
 			"streamsec_collection_token": schema.StringAttribute{
 				Description: "The Streamsec collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -101,10 +102,18 @@ func (r *AWSAccountResource) Schema(ctx context.Context, req resource.SchemaRequ
 			"account_auth_token": schema.StringAttribute{
 				Description: "The account auth token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -138,6 +147,8 @@ func (r *AWSAccountResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation CreateAccount($account_type: CloudProvider!, $cloud_account_id: String!, $display_name: String, $cloud_regions: [String]) {
 			createAccount(account: {
@@ -162,17 +173,13 @@ func (r *AWSAccountResource) Create(ctx context.Context, req resource.CreateRequ
 		"cloud_regions":    utils.ConvertToStringSlice(data.CloudRegions.Elements()), // Fix: Access the Value field directly
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err := r.client.DoRequest(query, variables)
+	res, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	account := res["createAccount"].(map[string]interface{})
 	data.ID = types.StringValue(account["_id"].(string))
 	data.TemplateURL = types.StringValue(account["template_url"].(string))
@@ -198,6 +205,8 @@ func (r *AWSAccountResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -214,7 +223,7 @@ func (r *AWSAccountResource) Read(ctx context.Context, req resource.ReadRequest,
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get accounts, got error: %s", err))
@@ -263,6 +272,8 @@ func (r *AWSAccountResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
 	if data.DisplayName != state.DisplayName || !utils.EqualListValues(data.CloudRegions, state.CloudRegions) {
 		query := `
@@ -278,9 +289,7 @@ func (r *AWSAccountResource) Update(ctx context.Context, req resource.UpdateRequ
 				"cloud_regions": utils.ConvertToStringSlice(data.CloudRegions.Elements()),
 				"display_name":  data.DisplayName.ValueString()}}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -303,6 +312,8 @@ func (r *AWSAccountResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation DeleteAccount($id: ID!) {
 			deleteAccount(id: $id)
@@ -311,7 +322,7 @@ func (r *AWSAccountResource) Delete(ctx context.Context, req resource.DeleteRequ
 	variables := map[string]interface{}{
 		"id": data.ID.ValueString()}
 
-	_, err := r.client.DoRequest(query, variables)
+	_, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete account, got error: %s", err))
@@ -6,8 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -23,11 +23,22 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AWSCostAckResource{}
 var _ resource.ResourceWithImportState = &AWSCostAckResource{}
+var _ resource.ResourceWithValidateConfig = &AWSCostAckResource{}
 
 func NewAWSCostAckResource() resource.Resource {
 	return &AWSCostAckResource{}
 }
 
+// Versions of the cost template, the Data Exports (CUR 2.0) template replaces the legacy
+// Cost and Usage Report one.
+const (
+	costTemplateVersionCUR         = 1
+	costTemplateVersionDataExports = 2
+)
+
+// defaultCostExportFormat is the format of Data Exports when export_format is not set.
+const defaultCostExportFormat = "Parquet"
+
 type CostRequestBody struct {
 	Operaion        string `json:"operation"`
 	TemplateVersion int    `json:"template_version"`
@@ -37,6 +48,10 @@ type CostRequestBody struct {
 	BucketARN       string `json:"bucket_arn"`
 	CURPrefix       string `json:"cur_prefix"`
 	ExternalID      string `json:"external_id"`
+	ExportName      string `json:"export_name,omitempty"`
+	ExportFormat    string `json:"export_format,omitempty"`
+	BucketRegion    string `json:"bucket_region,omitempty"`
+	KMSKeyARN       string `json:"kms_key_arn,omitempty"`
 }
 
 type AWSCostAckResource struct {
@@ -49,7 +64,15 @@ type AWSCostAckResourceModel struct {
 	ExternalID               types.String `tfsdk:"external_id"`
 	BucketARN                types.String `tfsdk:"bucket_arn"`
 	CURPrefix                types.String `tfsdk:"cur_prefix"`
+	ExportName               types.String `tfsdk:"export_name"`
+	ExportFormat             types.String `tfsdk:"export_format"`
+	BucketRegion             types.String `tfsdk:"bucket_region"`
+	KMSKeyARN                types.String `tfsdk:"kms_key_arn"`
+	IngestionStatus          types.String `tfsdk:"ingestion_status"`
+	LastFileProcessed        types.String `tfsdk:"last_file_processed"`
+	IngestionError           types.String `tfsdk:"ingestion_error"`
 	StreamsecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AWSCostAckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -77,48 +100,104 @@ func (r *AWSCostAckResource) Schema(ctx context.Context, req resource.SchemaRequ
 				},
 				// add validator to check if the cloud_account_id is a valid AWS account ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^(\d{12})$`), "The cloud account ID must be a 12-digit number."),
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
 				},
 			},
 			"role_arn": schema.StringAttribute{
 				Description: "The role_arn to access CUR bucket.",
 				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 			"external_id": schema.StringAttribute{
 				Description: "The external ID.",
 				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 			"bucket_arn": schema.StringAttribute{
 				Description: "The bucket arn.",
 				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.S3BucketARNRegex, "The bucket ARN must be an S3 bucket ARN, e.g. arn:aws:s3:::my-bucket."),
 				},
 			},
 			"cur_prefix": schema.StringAttribute{
-				Description: "The CUR prefix.",
-				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+				Description: "The CUR prefix, required for a legacy Cost and Usage Report.",
+				Optional:    true,
+			},
+			"export_name": schema.StringAttribute{
+				Description: "The name of the AWS Data Exports (CUR 2.0) export. Omit for a legacy Cost and Usage Report.",
+				Optional:    true,
+			},
+			"export_format": schema.StringAttribute{
+				Description: "The format of the Data Exports export: Parquet or CSV. Defaults to Parquet.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("Parquet", "CSV"),
+					stringvalidator.AlsoRequires(path.MatchRoot("export_name")),
+				},
+			},
+			"bucket_region": schema.StringAttribute{
+				Description: "The region of the bucket.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.AWSRegionRegex, "The bucket region must be a valid AWS region, e.g. us-east-1."),
+				},
+			},
+			"kms_key_arn": schema.StringAttribute{
+				Description: "The ARN of the KMS key encrypting the bucket.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.KMSKeyARNRegex, "The KMS key ARN must be a KMS key ARN, e.g. arn:aws:kms:us-east-1:123456789012:key/<key-id>."),
 				},
 			},
+			"ingestion_status": schema.StringAttribute{
+				Description: "The status of the cost ingestion.",
+				Computed:    true,
+			},
+			"last_file_processed": schema.StringAttribute{
+				Description: "The last cost report file processed.",
+				Computed:    true,
+			},
+			"ingestion_error": schema.StringAttribute{
+				Description: "The last cost ingestion error, if any.",
+				Computed:    true,
+			},
 			"streamsec_collection_token": schema.StringAttribute{
 				Description: "The collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *AWSCostAckResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AWSCostAckResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.ExportName.IsUnknown() || data.CURPrefix.IsUnknown() {
+		return
+	}
+
+	// Data Exports write under the export name, only the legacy report needs a prefix
+	if data.ExportName.IsNull() && data.CURPrefix.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cur_prefix"),
+			"Missing Attribute Configuration",
+			"cur_prefix is required unless export_name is set.",
+		)
+	}
+}
+
 func (r *AWSCostAckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -148,96 +227,37 @@ func (r *AWSCostAckResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				stack_region
-				template_url
-				external_id
-				lightlytics_collection_token
-				account_auth_token
-				cost {
-					status
-					role_arn
-					external_id
-					bucket_arn
-					cur_prefix
-				}
-			}
-		}`
+	client := workspaceClient(r.client, data.WorkspaceID)
 
-	res, err := r.client.DoRequest(query, nil)
+	account, err := getCostAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
-
-	for _, acc := range accounts {
-
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			accountFound = true
-		}
-	}
-
-	if !accountFound {
+	if account == nil {
 		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
 		return
 	}
 
-	body := CostRequestBody{
-		TemplateVersion: 1,
-		Operaion:        "Create",
-		Details:         "",
-		Status:          "success",
-		RoleARN:         data.RoleARN.ValueString(),
-		ExternalID:      data.ExternalID.ValueString(),
-		BucketARN:       data.BucketARN.ValueString(),
-		CURPrefix:       data.CURPrefix.ValueString(),
-	}
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
 
-	jsonData, err := json.Marshal(body)
-	if err != nil {
-		fmt.Printf("Error marshalling JSON: %s\n", err)
-		return
-	}
-
-	url := fmt.Sprintf("https://%s/api/v1/collection/cost/cft", r.client.Host)
-
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	ackReq.Header.Set("X-Lightlytics-Token", data.StreamsecCollectionToken.ValueString())
-
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
+	if err := sendCostRequest(ctx, client, costRequestBody("Create", data), data.StreamsecCollectionToken.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable cost collection, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	// Ingestion starts after the ack, read back its initial status
+	account, err = getCostAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
-		return
-	}
-
-	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
+	setCostIngestion(&data, account)
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -257,68 +277,90 @@ func (r *AWSCostAckResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				template_url
-				external_id
-				lightlytics_collection_token
-				account_auth_token
-				cost {
-					status
-					role_arn
-					external_id
-					bucket_arn
-					cur_prefix
-				}
-			}
-		}`
+	client := workspaceClient(r.client, data.WorkspaceID)
 
-	res, err := r.client.DoRequest(query, nil)
+	account, err := getCostAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
-
-	for _, acc := range accounts {
-
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			accountFound = true
-		}
+	if account == nil {
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
-	if !accountFound {
+	if costDisabled(account) {
+		resp.Diagnostics.AddWarning(
+			"Cost collection disabled",
+			fmt.Sprintf("Cost collection of account %s was disabled outside of Terraform, it will be enabled again.", data.CloudAccountID.ValueString()),
+		)
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+	setCostIngestion(&data, account)
+
+	// Nothing is reported until the ack is processed, keep the planned configuration until then
+	if costStatus(account) != "" {
+		setCostConfiguration(&data, account)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AWSCostAckResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data AWSCostAckResourceModel
-	var state AWSCostAckResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getCostAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	if account == nil {
+		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
+		return
+	}
+
+	if costDisabled(account) {
+		resp.Diagnostics.AddError(
+			"Cost collection disabled",
+			fmt.Sprintf("Cost collection of account %s was disabled outside of Terraform and cannot be updated. "+
+				"Refresh the state, or replace the resource to enable it again.", data.CloudAccountID.ValueString()),
+		)
+		return
+	}
+
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+
+	if err := sendCostRequest(ctx, client, costRequestBody("Update", data), data.StreamsecCollectionToken.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update cost collection, got error: %s", err))
+		return
+	}
+
+	account, err = getCostAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	setCostIngestion(&data, account)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -333,48 +375,158 @@ func (r *AWSCostAckResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	if err := sendCostRequest(ctx, client, costRequestBody("Delete", data), data.StreamsecCollectionToken.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable cost collection, got error: %s", err))
+		return
+	}
+}
+
+func (r *AWSCostAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("cloud_account_id"), req, resp)
+}
+
+// costRequestBody builds the cost template request for the operation, picking the template
+// version from whether a Data Exports export is configured.
+func costRequestBody(operation string, data AWSCostAckResourceModel) CostRequestBody {
 	body := CostRequestBody{
-		Operaion:        "Delete",
-		TemplateVersion: 1,
+		TemplateVersion: costTemplateVersionCUR,
+		Operaion:        operation,
 		Details:         "",
 		Status:          "success",
 		RoleARN:         data.RoleARN.ValueString(),
 		ExternalID:      data.ExternalID.ValueString(),
 		BucketARN:       data.BucketARN.ValueString(),
 		CURPrefix:       data.CURPrefix.ValueString(),
+		BucketRegion:    data.BucketRegion.ValueString(),
+		KMSKeyARN:       data.KMSKeyARN.ValueString(),
 	}
 
-	jsonData, err := json.Marshal(body)
+	if !data.ExportName.IsNull() {
+		body.TemplateVersion = costTemplateVersionDataExports
+		body.ExportName = data.ExportName.ValueString()
+		body.ExportFormat = defaultCostExportFormat
+		if !data.ExportFormat.IsNull() {
+			body.ExportFormat = data.ExportFormat.ValueString()
+		}
+	}
+
+	return body
+}
+
+// getCostAccount returns the account with its cost configuration, or nil if it does not exist.
+func getCostAccount(ctx context.Context, client *client.Client, cloudAccountID string) (map[string]interface{}, error) {
+	query := `
+		query {
+			accounts {
+				_id
+				cloud_account_id
+				lightlytics_collection_token
+				cost {
+					status
+					role_arn
+					external_id
+					bucket_arn
+					cur_prefix
+					export_name
+					export_format
+					bucket_region
+					kms_key_arn
+					ingestion_status
+					last_file_processed
+					ingestion_error
+				}
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
 	if err != nil {
-		fmt.Printf("Error marshalling JSON: %s\n", err)
-		return
+		return nil, err
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/collection/cost/cft", r.client.Host)
+	for _, acc := range res["accounts"].([]interface{}) {
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"].(string) == cloudAccountID {
+			return account, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// costDisabledStatus is the cost status of accounts whose cost collection was disabled.
+const costDisabledStatus = "DISABLED"
+
+// costStatus returns the cost status of the account, empty while the ack is not processed yet.
+func costStatus(account map[string]interface{}) string {
+	cost, _ := account["cost"].(map[string]interface{})
+	status, _ := cost["status"].(string)
+	return status
+}
+
+// costDisabled reports whether cost collection of the account was disabled. An empty status is
+// transitional, the resource is kept until the ack is processed.
+func costDisabled(account map[string]interface{}) bool {
+	return costStatus(account) == costDisabledStatus
+}
+
+// setCostConfiguration reads back the cost configuration, so changes made outside of
+// Terraform show as drift.
+func setCostConfiguration(data *AWSCostAckResourceModel, account map[string]interface{}) {
+	cost := account["cost"].(map[string]interface{})
+	data.RoleARN = utils.ConvertInterfaceToTypesString(cost["role_arn"])
+	data.ExternalID = utils.ConvertInterfaceToTypesString(cost["external_id"])
+	data.BucketARN = utils.ConvertInterfaceToTypesString(cost["bucket_arn"])
+	data.CURPrefix = utils.ConvertInterfaceToOptionalTypesString(cost["cur_prefix"])
+	data.ExportName = utils.ConvertInterfaceToOptionalTypesString(cost["export_name"])
+	data.BucketRegion = utils.ConvertInterfaceToOptionalTypesString(cost["bucket_region"])
+	data.KMSKeyARN = utils.ConvertInterfaceToOptionalTypesString(cost["kms_key_arn"])
+
+	// The default format is only kept when it was configured
+	exportFormat := utils.ConvertInterfaceToOptionalTypesString(cost["export_format"])
+	if data.ExportFormat.IsNull() && exportFormat.ValueString() == defaultCostExportFormat {
+		exportFormat = types.StringNull()
+	}
+	data.ExportFormat = exportFormat
+}
+
+// sendCostRequest posts a cost template event with the account collection token.
+func sendCostRequest(ctx context.Context, client *client.Client, body CostRequestBody, collectionToken string) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	ackReq.Header.Set("X-Lightlytics-Token", data.StreamsecCollectionToken.ValueString())
+	url := fmt.Sprintf("https://%s/api/v1/collection/cost/cft", client.Host)
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
-		return
+		return err
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ackReq.Header.Set("X-Lightlytics-Token", collectionToken)
+
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
-		return
+		return err
 	}
+	defer ack.Body.Close()
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
-		return
+		return client.ResponseError(ack)
 	}
+
+	return nil
 }
 
-func (r *AWSCostAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("cloud_account_id"), req, resp)
+// setCostIngestion copies the cost ingestion status of the account, null until ingestion starts.
+func setCostIngestion(data *AWSCostAckResourceModel, account map[string]interface{}) {
+	cost, _ := account["cost"].(map[string]interface{})
+	data.IngestionStatus = utils.ConvertInterfaceToTypesString(cost["ingestion_status"])
+	data.LastFileProcessed = utils.ConvertInterfaceToTypesString(cost["last_file_processed"])
+	data.IngestionError = utils.ConvertInterfaceToTypesString(cost["ingestion_error"])
 }
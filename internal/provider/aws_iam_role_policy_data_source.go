@@ -0,0 +1,437 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+// iamPolicyResourceTypes are the CloudFormation resource types holding IAM policy documents.
+var iamPolicyResourceTypes = map[string]bool{
+	"AWS::IAM::Role":          true,
+	"AWS::IAM::Policy":        true,
+	"AWS::IAM::ManagedPolicy": true,
+}
+
+// templateSubVariableRegex matches the ${Name} and ${!Literal} variables of Fn::Sub.
+var templateSubVariableRegex = regexp.MustCompile(`\$\{(!?)([^}]*)\}`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AWSIAMRolePolicyDataSource{}
+
+func NewAWSIAMRolePolicyDataSource() datasource.DataSource {
+	return &AWSIAMRolePolicyDataSource{}
+}
+
+// AWSIAMRolePolicyDataSource defines the data source implementation.
+type AWSIAMRolePolicyDataSource struct {
+	client *client.Client
+}
+
+// AWSIAMRolePolicyDataSourceModel describes the data source data model.
+type AWSIAMRolePolicyDataSourceModel struct {
+	Collection        types.Bool   `tfsdk:"collection"`
+	Cost              types.Bool   `tfsdk:"cost"`
+	CostBucketARN     types.String `tfsdk:"cost_bucket_arn"`
+	RealTimeEvents    types.Bool   `tfsdk:"real_time_events"`
+	Remediation       types.Bool   `tfsdk:"remediation"`
+	Partition         types.String `tfsdk:"partition"`
+	JSON              types.String `tfsdk:"json"`
+	ManagedPolicyARNs types.List   `tfsdk:"managed_policy_arns"`
+	WorkspaceID       types.String `tfsdk:"workspace_id"`
+}
+
+func (d *AWSIAMRolePolicyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_iam_role_policy"
+}
+
+func (d *AWSIAMRolePolicyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The permissions of the IAM role Stream.Security uses for the enabled features, for roles created with " +
+			"Terraform instead of the onboarding CloudFormation template. The feature permissions are read from the statements of the " +
+			"feature templates Stream.Security currently deploys; statements scoped to resources a template creates are left out with a warning.",
+
+		Attributes: map[string]schema.Attribute{
+			"collection": schema.BoolAttribute{
+				MarkdownDescription: "Read-only collection of the account resources. Defaults to `true`.",
+				Optional:            true,
+			},
+			"cost": schema.BoolAttribute{
+				MarkdownDescription: "Cost collection from the cost and usage reports bucket.",
+				Optional:            true,
+			},
+			"cost_bucket_arn": schema.StringAttribute{
+				MarkdownDescription: "The ARN of the cost and usage reports bucket, required with `cost`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.S3BucketARNRegex, "The bucket ARN must be an S3 bucket ARN, e.g. arn:aws:s3:::my-bucket."),
+				},
+			},
+			"real_time_events": schema.BoolAttribute{
+				MarkdownDescription: "Real-time events collection through EventBridge.",
+				Optional:            true,
+			},
+			"remediation": schema.BoolAttribute{
+				MarkdownDescription: "Remediation runbooks.",
+				Optional:            true,
+			},
+			"partition": schema.StringAttribute{
+				MarkdownDescription: "The AWS partition of the role. Defaults to `aws`.",
+				Optional:            true,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The inline policy document, null when only managed policies are needed.",
+				Computed:            true,
+			},
+			"managed_policy_arns": schema.ListAttribute{
+				MarkdownDescription: "The AWS managed policies to attach to the role.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (d *AWSIAMRolePolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AWSIAMRolePolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AWSIAMRolePolicyDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	partition := "aws"
+	if !data.Partition.IsNull() {
+		partition = data.Partition.ValueString()
+	}
+
+	managedPolicyARNs := []string{}
+	if data.Collection.IsNull() || data.Collection.ValueBool() {
+		managedPolicyARNs = append(managedPolicyARNs, fmt.Sprintf("arn:%s:iam::aws:policy/ReadOnlyAccess", partition))
+	}
+
+	if data.Cost.ValueBool() && data.CostBucketARN.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("cost_bucket_arn"), "Missing Attribute Configuration", "cost_bucket_arn is required when cost is enabled.")
+		return
+	}
+
+	var statements []utils.IAMPolicyStatement
+
+	// The pseudo parameters a template may reference outside of its stack
+	urlSuffix := "amazonaws.com"
+	if partition == "aws-cn" {
+		urlSuffix = "amazonaws.com.cn"
+	}
+	variables := map[string]string{
+		"AWS::Partition": partition,
+		"AWS::URLSuffix": urlSuffix,
+	}
+
+	if data.Cost.ValueBool() {
+		bucketARN := data.CostBucketARN.ValueString()
+		statements = append(statements,
+			utils.IAMPolicyStatement{
+				Sid:      "StreamSecurityCostBucket",
+				Effect:   "Allow",
+				Action:   []string{"s3:GetBucketLocation", "s3:ListBucket"},
+				Resource: bucketARN,
+			},
+			utils.IAMPolicyStatement{
+				Sid:      "StreamSecurityCostReports",
+				Effect:   "Allow",
+				Action:   []string{"s3:GetObject"},
+				Resource: bucketARN + "/*",
+			},
+		)
+	}
+
+	features := []struct {
+		enabled bool
+		stack   string
+	}{
+		{data.Cost.ValueBool(), "cost"},
+		{data.RealTimeEvents.ValueBool(), "real_time_events"},
+		{data.Remediation.ValueBool(), "remediation"},
+	}
+
+	// The cost bucket statements above replace the ones of the cost template, which reference
+	// the bucket through a stack parameter
+	sids := map[string]bool{}
+	for _, statement := range statements {
+		sids[statement.Sid] = true
+	}
+
+	client := workspaceClient(d.client, data.WorkspaceID)
+
+	for _, feature := range features {
+		if !feature.enabled {
+			continue
+		}
+
+		_, body, diags := getAWSTemplateBody(ctx, client, feature.stack, "")
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		templateStatements, skipped, err := templatePolicyStatements(body, variables)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Template Error", fmt.Sprintf("Unable to read the policies of the %s template, got error: %s", feature.stack, err))
+			return
+		}
+
+		for _, statement := range templateStatements {
+			if statement.Sid != "" {
+				if sids[statement.Sid] {
+					continue
+				}
+				sids[statement.Sid] = true
+			}
+			statements = append(statements, statement)
+		}
+
+		unresolved := []string{}
+		for _, sid := range skipped {
+			if !sids[sid] {
+				unresolved = append(unresolved, sid)
+			}
+		}
+
+		if len(unresolved) > 0 {
+			resp.Diagnostics.AddWarning("Template Statements Skipped", fmt.Sprintf("The statements %s of the %s template reference resources "+
+				"of its stack and are not part of the policy, grant them to the role separately if needed.", strings.Join(unresolved, ", "), feature.stack))
+		}
+	}
+
+	data.JSON = types.StringNull()
+	if len(statements) > 0 {
+		policy, err := utils.NewIAMPolicyDocument(statements...).JSON()
+
+		if err != nil {
+			resp.Diagnostics.AddError("Policy Error", fmt.Sprintf("Unable to render role policy, got error: %s", err))
+			return
+		}
+
+		data.JSON = types.StringValue(policy)
+	}
+
+	data.ManagedPolicyARNs = utils.ConvertStringsArrayToTypesList(managedPolicyARNs)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// templatePolicyStatements returns the Allow statements of the IAM policies of a CloudFormation
+// template, resolving Ref and Fn::Sub against the given variables. Statements using other intrinsic
+// functions or variables, e.g. to reference resources of the stack, cannot be resolved outside of it;
+// they are left out and returned by Sid, or by resource and position when they have none.
+func templatePolicyStatements(body string, variables map[string]string) ([]utils.IAMPolicyStatement, []string, error) {
+	var template struct {
+		Resources map[string]struct {
+			Type       string    `yaml:"Type"`
+			Properties yaml.Node `yaml:"Properties"`
+		} `yaml:"Resources"`
+	}
+
+	if err := yaml.Unmarshal([]byte(body), &template); err != nil {
+		return nil, nil, err
+	}
+
+	// Go maps are unordered, sort the resources so the policy only changes with the template
+	names := make([]string, 0, len(template.Resources))
+	for name := range template.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	statements := []utils.IAMPolicyStatement{}
+	skipped := []string{}
+
+	for _, name := range names {
+		resource := template.Resources[name]
+		if !iamPolicyResourceTypes[resource.Type] {
+			continue
+		}
+
+		var properties struct {
+			PolicyDocument yaml.Node `yaml:"PolicyDocument"`
+			Policies       []struct {
+				PolicyDocument yaml.Node `yaml:"PolicyDocument"`
+			} `yaml:"Policies"`
+		}
+
+		if err := resource.Properties.Decode(&properties); err != nil {
+			return nil, nil, fmt.Errorf("resource %s: %w", name, err)
+		}
+
+		documents := []yaml.Node{properties.PolicyDocument}
+		for _, policy := range properties.Policies {
+			documents = append(documents, policy.PolicyDocument)
+		}
+
+		for _, document := range documents {
+			if document.Kind != yaml.MappingNode {
+				continue
+			}
+
+			var policy struct {
+				Statement yaml.Node `yaml:"Statement"`
+			}
+
+			if err := document.Decode(&policy); err != nil {
+				return nil, nil, fmt.Errorf("resource %s: %w", name, err)
+			}
+
+			// A single statement may be given without a list
+			statementNodes := []*yaml.Node{&policy.Statement}
+			if policy.Statement.Kind == yaml.SequenceNode {
+				statementNodes = policy.Statement.Content
+			}
+
+			for i, statementNode := range statementNodes {
+				var statement struct {
+					Sid      string    `yaml:"Sid"`
+					Effect   string    `yaml:"Effect"`
+					Action   yaml.Node `yaml:"Action"`
+					Resource yaml.Node `yaml:"Resource"`
+				}
+
+				if statementNode.Kind != yaml.MappingNode || statementNode.Decode(&statement) != nil || statement.Effect != "Allow" {
+					continue
+				}
+
+				actions, ok := resolveStrings(&statement.Action, variables)
+				if !ok {
+					skipped = append(skipped, statementName(name, i, statement.Sid))
+					continue
+				}
+
+				resources, ok := resolveStrings(&statement.Resource, variables)
+				if !ok {
+					skipped = append(skipped, statementName(name, i, statement.Sid))
+					continue
+				}
+
+				var resource interface{} = resources
+				if len(resources) == 1 {
+					resource = resources[0]
+				}
+
+				statements = append(statements, utils.IAMPolicyStatement{
+					Sid:      statement.Sid,
+					Effect:   "Allow",
+					Action:   actions,
+					Resource: resource,
+				})
+			}
+		}
+	}
+
+	return statements, skipped, nil
+}
+
+// statementName names a template statement by its Sid, or by its resource and position.
+func statementName(resource string, index int, sid string) string {
+	if sid != "" {
+		return sid
+	}
+	return fmt.Sprintf("%s[%d]", resource, index)
+}
+
+// resolveStrings returns the strings of a scalar or a list of scalars, resolving Ref and Fn::Sub
+// in both their short and full forms. False when the node is empty, uses another intrinsic function
+// such as Fn::Join or references an unknown variable.
+func resolveStrings(node *yaml.Node, variables map[string]string) ([]string, bool) {
+	nodes := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		nodes = node.Content
+	}
+
+	values := []string{}
+	for _, n := range nodes {
+		function, argument := n.Tag, n
+		if n.Kind == yaml.MappingNode && len(n.Content) == 2 {
+			function, argument = n.Content[0].Value, n.Content[1]
+		}
+
+		if argument.Kind != yaml.ScalarNode {
+			return nil, false
+		}
+
+		switch function {
+		case "!!str":
+			values = append(values, argument.Value)
+		case "!Ref", "Ref":
+			value, ok := variables[argument.Value]
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		case "!Sub", "Fn::Sub":
+			resolved := true
+			value := templateSubVariableRegex.ReplaceAllStringFunc(argument.Value, func(match string) string {
+				groups := templateSubVariableRegex.FindStringSubmatch(match)
+				if groups[1] == "!" {
+					return "${" + groups[2] + "}"
+				}
+				variable, ok := variables[groups[2]]
+				resolved = resolved && ok
+				return variable
+			})
+			if !resolved {
+				return nil, false
+			}
+			values = append(values, value)
+		default:
+			return nil, false
+		}
+	}
+
+	return values, len(values) > 0
+}
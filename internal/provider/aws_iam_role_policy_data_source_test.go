@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"reflect"
+	"terraform-provider-streamsec/internal/utils"
+	"testing"
+)
+
+func TestTemplatePolicyStatements(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		want     []utils.IAMPolicyStatement
+		skipped  []string
+		wantErr  bool
+	}{
+		{
+			name: "role policies",
+			template: `
+Parameters:
+  ExternalId:
+    Type: String
+Resources:
+  Role:
+    Type: AWS::IAM::Role
+    Properties:
+      RoleName: !Sub "streamsec-${AWS::Region}"
+      Policies:
+        - PolicyName: events
+          PolicyDocument:
+            Version: "2012-10-17"
+            Statement:
+              - Sid: Events
+                Effect: Allow
+                Action:
+                  - events:PutRule
+                  - events:PutTargets
+                Resource: "*"
+              - Sid: Logs
+                Effect: Allow
+                Action: logs:PutLogEvents
+                Resource:
+                  - !Sub "arn:${AWS::Partition}:logs:*:*:log-group:streamsec-${!Suffix}"
+                  - !Ref LogGroupArn
+              - Effect: Allow
+                Action: sqs:SendMessage
+                Resource: !GetAtt Queue.Arn
+              - Effect: Deny
+                Action: "*"
+                Resource: "*"
+  Queue:
+    Type: AWS::SQS::Queue
+`,
+			want: []utils.IAMPolicyStatement{
+				{Sid: "Events", Effect: "Allow", Action: []string{"events:PutRule", "events:PutTargets"}, Resource: "*"},
+				{Sid: "Logs", Effect: "Allow", Action: []string{"logs:PutLogEvents"}, Resource: []string{
+					"arn:aws:logs:*:*:log-group:streamsec-${Suffix}",
+					"arn:aws:logs:*:*:log-group:streamsec",
+				}},
+			},
+			skipped: []string{"Role[2]"},
+		},
+		{
+			name: "managed policy in JSON",
+			template: `{
+  "Resources": {
+    "Policy": {
+      "Type": "AWS::IAM::ManagedPolicy",
+      "Properties": {
+        "PolicyDocument": {
+          "Statement": {
+            "Effect": "Allow",
+            "Action": ["cur:DescribeReportDefinitions"],
+            "Resource": ["*"]
+          }
+        }
+      }
+    },
+    "Joined": {
+      "Type": "AWS::IAM::Policy",
+      "Properties": {
+        "PolicyDocument": {
+          "Statement": [{
+            "Sid": "Joined",
+            "Effect": "Allow",
+            "Action": "s3:GetObject",
+            "Resource": {"Fn::Join": ["", ["arn:aws:s3:::", {"Ref": "Bucket"}, "/*"]]}
+          }, {
+            "Sid": "Bucket",
+            "Effect": "Allow",
+            "Action": "s3:ListBucket",
+            "Resource": {"Fn::Sub": "arn:${AWS::Partition}:s3:::${Bucket}"}
+          }, {
+            "Effect": "Allow",
+            "Action": "s3:ListAllMyBuckets",
+            "Resource": {"Fn::Sub": "arn:${AWS::Partition}:s3:::*"}
+          }]
+        }
+      }
+    }
+  }
+}`,
+			want: []utils.IAMPolicyStatement{
+				{Effect: "Allow", Action: []string{"s3:ListAllMyBuckets"}, Resource: "arn:aws:s3:::*"},
+				{Effect: "Allow", Action: []string{"cur:DescribeReportDefinitions"}, Resource: "*"},
+			},
+			skipped: []string{"Joined", "Bucket"},
+		},
+		{
+			name:     "no policies",
+			template: "Resources:\n  Queue:\n    Type: AWS::SQS::Queue\n",
+			want:     []utils.IAMPolicyStatement{},
+			skipped:  []string{},
+		},
+		{
+			name:     "invalid",
+			template: "Resources: [",
+			wantErr:  true,
+		},
+	}
+
+	variables := map[string]string{
+		"AWS::Partition": "aws",
+		"LogGroupArn":    "arn:aws:logs:*:*:log-group:streamsec",
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, skipped, err := templatePolicyStatements(tc.template, variables)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("templatePolicyStatements() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("templatePolicyStatements() = %+v, want %+v", got, tc.want)
+			}
+			if !reflect.DeepEqual(skipped, tc.skipped) {
+				t.Errorf("templatePolicyStatements() skipped = %v, want %v", skipped, tc.skipped)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ type AWSKubernetesClusterResourceModel struct {
 	Status          types.String `tfsdk:"status"`
 	CollectionToken types.String `tfsdk:"collection_token"`
 	CreationDate    types.String `tfsdk:"creation_date"`
+	WorkspaceID     types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AWSKubernetesClusterResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -72,6 +73,7 @@ func (r *AWSKubernetesClusterResource) Schema(ctx context.Context, req resource.
 			"collection_token": schema.StringAttribute{
 				Description: "The collection_token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -83,6 +85,13 @@ func (r *AWSKubernetesClusterResource) Schema(ctx context.Context, req resource.
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -116,6 +125,8 @@ func (r *AWSKubernetesClusterResource) Create(ctx context.Context, req resource.
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation CreateKubernetes($display_name: String, $arn: String) {
 			createKubernetes(kubernetes: {
@@ -134,17 +145,13 @@ func (r *AWSKubernetesClusterResource) Create(ctx context.Context, req resource.
 		"display_name": data.DisplayName.ValueString(),
 		"arn":          data.ARN.ValueString()}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err := r.client.DoRequest(query, variables)
+	res, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	cluster := res["createKubernetes"].(map[string]interface{})
 	data.ID = types.StringValue(cluster["_id"].(string))
 	data.Status = types.StringValue(cluster["status"].(string))
@@ -169,6 +176,8 @@ func (r *AWSKubernetesClusterResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			kubernetes {
@@ -181,7 +190,7 @@ func (r *AWSKubernetesClusterResource) Read(ctx context.Context, req resource.Re
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -224,6 +233,8 @@ func (r *AWSKubernetesClusterResource) Update(ctx context.Context, req resource.
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
 	if data.DisplayName != state.DisplayName {
 		query := `
@@ -238,9 +249,7 @@ func (r *AWSKubernetesClusterResource) Update(ctx context.Context, req resource.
 			"kubernetes": map[string]interface{}{
 				"display_name": data.DisplayName.ValueString()}}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -263,6 +272,8 @@ func (r *AWSKubernetesClusterResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation DeleteKubernetes($id: ID!) {
 			deleteKubernetes(id: $id)
@@ -271,7 +282,7 @@ func (r *AWSKubernetesClusterResource) Delete(ctx context.Context, req resource.
 	variables := map[string]interface{}{
 		"id": data.ID.ValueString()}
 
-	_, err := r.client.DoRequest(query, variables)
+	_, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete account, got error: %s", err))
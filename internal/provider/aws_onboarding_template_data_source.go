@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AWSOnboardingTemplateDataSource{}
+
+func NewAWSOnboardingTemplateDataSource() datasource.DataSource {
+	return &AWSOnboardingTemplateDataSource{}
+}
+
+// AWSOnboardingTemplateDataSource defines the data source implementation.
+type AWSOnboardingTemplateDataSource struct {
+	client *client.Client
+}
+
+// AWSOnboardingTemplateDataSourceModel describes the data source data model.
+type AWSOnboardingTemplateDataSourceModel struct {
+	Stack          types.String `tfsdk:"stack"`
+	Version        types.String `tfsdk:"version"`
+	CloudAccountID types.String `tfsdk:"cloud_account_id"`
+	TemplateURL    types.String `tfsdk:"template_url"`
+	Body           types.String `tfsdk:"body"`
+	Parameters     types.Map    `tfsdk:"parameters"`
+	SHA256         types.String `tfsdk:"sha256"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
+}
+
+func (d *AWSOnboardingTemplateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_onboarding_template"
+}
+
+func (d *AWSOnboardingTemplateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Fetches a Stream.Security CloudFormation template, so template upgrades can be reviewed in plans.",
+
+		Attributes: map[string]schema.Attribute{
+			"stack": schema.StringAttribute{
+				MarkdownDescription: "The stack of the template: `account`, `cost`, `real_time_events` or `remediation`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("account", "cost", "real_time_events", "remediation"),
+				},
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The template version. Defaults to the version Stream.Security currently deploys.",
+				Optional:            true,
+			},
+			"cloud_account_id": schema.StringAttribute{
+				MarkdownDescription: "Fetch the `account` template of this AWS account, the one its `template_url` deploys. " +
+					"Conflicts with `version`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
+					stringvalidator.ConflictsWith(path.MatchRoot("version")),
+				},
+			},
+			"template_url": schema.StringAttribute{
+				MarkdownDescription: "The URL the template was fetched from.",
+				Computed:            true,
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "The template body.",
+				Computed:            true,
+			},
+			"parameters": schema.MapAttribute{
+				MarkdownDescription: "The template parameters and their default values.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "The SHA256 of the template body.",
+				Computed:            true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (d *AWSOnboardingTemplateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AWSOnboardingTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AWSOnboardingTemplateDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(d.client, data.WorkspaceID)
+
+	var templateURL, templateBody string
+
+	if !data.CloudAccountID.IsNull() {
+		if data.Stack.ValueString() != "account" {
+			resp.Diagnostics.AddAttributeError(path.Root("cloud_account_id"), "Invalid Attribute Combination", "cloud_account_id can only be used with the account stack.")
+			return
+		}
+
+		accountURL, diags := accountTemplateURL(ctx, client, data.CloudAccountID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		templateURL = accountURL
+
+		body, err := fetchTemplate(ctx, client, templateURL)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to fetch template %s, got error: %s", templateURL, err))
+			return
+		}
+
+		templateBody = body
+	} else {
+		var diags diag.Diagnostics
+		templateURL, templateBody, diags = getAWSTemplateBody(ctx, client, data.Stack.ValueString(), data.Version.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Only the parameters are decoded, the rest of the template may use CloudFormation tags
+	var template struct {
+		Parameters map[string]struct {
+			Default interface{} `yaml:"Default"`
+		} `yaml:"Parameters"`
+	}
+
+	if err := yaml.Unmarshal([]byte(templateBody), &template); err != nil {
+		resp.Diagnostics.AddError("Template Error", fmt.Sprintf("Unable to parse template parameters, got error: %s", err))
+		return
+	}
+
+	parameters := map[string]interface{}{}
+	for name, parameter := range template.Parameters {
+		parameters[name] = ""
+		if parameter.Default != nil {
+			parameters[name] = fmt.Sprint(parameter.Default)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(templateBody))
+
+	data.TemplateURL = types.StringValue(templateURL)
+	data.Body = types.StringValue(templateBody)
+	data.Parameters = utils.ConvertInterfaceToTypesMap(parameters)
+	data.SHA256 = types.StringValue(hex.EncodeToString(sum[:]))
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// getAWSTemplate returns the URL, and body when available, of a template version of the stack.
+// An empty version selects the version Stream.Security currently deploys.
+func getAWSTemplate(ctx context.Context, client *client.Client, stack string, version string) (map[string]interface{}, error) {
+	query := `
+		query awsTemplate($stack: String!, $version: String) {
+			awsTemplate(stack: $stack, version: $version) {
+				template_url
+				body
+			}
+		}`
+
+	variables := map[string]interface{}{"stack": stack}
+	if version != "" {
+		variables["version"] = version
+	}
+
+	res, err := client.DoRequest(ctx, query, variables)
+
+	if err != nil {
+		return nil, err
+	}
+
+	template, ok := res["awsTemplate"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("template %s version %q not found", stack, version)
+	}
+
+	return template, nil
+}
+
+// getAWSTemplateBody returns the URL and body of a template version of the stack, downloading the
+// body when the API only returns the URL.
+func getAWSTemplateBody(ctx context.Context, client *client.Client, stack string, version string) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	template, err := getAWSTemplate(ctx, client, stack, version)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get template, got error: %s", err))
+		return "", "", diags
+	}
+
+	templateURL, _ := template["template_url"].(string)
+	templateBody, _ := template["body"].(string)
+
+	if templateURL == "" && templateBody == "" {
+		diags.AddError("Template not found", fmt.Sprintf("Stream.Security returned no %s template.", stack))
+		return "", "", diags
+	}
+
+	// The API only returns the body of some templates, the others are downloaded
+	if templateBody == "" {
+		templateBody, err = fetchTemplate(ctx, client, templateURL)
+
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to fetch template %s, got error: %s", templateURL, err))
+			return "", "", diags
+		}
+	}
+
+	return templateURL, templateBody, diags
+}
+
+// accountTemplateURL returns the URL of the template deployed by the template_url of the account.
+// template_url is a CloudFormation console quick-create link, the template is its templateURL parameter.
+func accountTemplateURL(ctx context.Context, client *client.Client, cloudAccountID string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	query := `
+		query {
+			accounts {
+				cloud_account_id
+				template_url
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return "", diags
+	}
+
+	for _, acc := range res["accounts"].([]interface{}) {
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"] == cloudAccountID {
+			quickCreateURL, _ := account["template_url"].(string)
+			templateURL, err := quickCreateTemplateURL(quickCreateURL)
+			if err != nil {
+				diags.AddError("Template not found", fmt.Sprintf("Unable to read the template of account %s, got error: %s", cloudAccountID, err))
+				return "", diags
+			}
+			return templateURL, diags
+		}
+	}
+
+	diags.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", cloudAccountID))
+	return "", diags
+}
+
+// quickCreateTemplateURL extracts the templateURL parameter of a CloudFormation quick-create link,
+// which the console keeps in the URL fragment.
+func quickCreateTemplateURL(quickCreateURL string) (string, error) {
+	if quickCreateURL == "" {
+		return "", fmt.Errorf("the account has no template_url")
+	}
+
+	parsed, err := url.Parse(quickCreateURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	if _, fragmentQuery, found := strings.Cut(parsed.Fragment, "?"); found && query.Get("templateURL") == "" {
+		query, err = url.ParseQuery(fragmentQuery)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	templateURL := query.Get("templateURL")
+	if templateURL == "" {
+		return "", fmt.Errorf("no templateURL parameter in %s", quickCreateURL)
+	}
+
+	return templateURL, nil
+}
+
+// fetchTemplate downloads a template body through the client, so it is rate limited and logged.
+func fetchTemplate(ctx context.Context, client *client.Client, templateURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", templateURL, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", client.ResponseError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
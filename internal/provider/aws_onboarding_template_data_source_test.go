@@ -0,0 +1,45 @@
+package provider
+
+import "testing"
+
+func TestQuickCreateTemplateURL(t *testing.T) {
+	cases := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "fragment",
+			url:  "https://us-east-1.console.aws.amazon.com/cloudformation/home?region=us-east-1#/stacks/quickcreate?templateURL=https%3A%2F%2Fbucket.s3.amazonaws.com%2Ftemplate.yaml&stackName=StreamSecurity",
+			want: "https://bucket.s3.amazonaws.com/template.yaml",
+		},
+		{
+			name: "query",
+			url:  "https://console.aws.amazon.com/cloudformation/home?region=us-east-1&templateURL=https%3A%2F%2Fbucket.s3.amazonaws.com%2Ftemplate.yaml",
+			want: "https://bucket.s3.amazonaws.com/template.yaml",
+		},
+		{
+			name:    "empty",
+			url:     "",
+			wantErr: true,
+		},
+		{
+			name:    "no template",
+			url:     "https://console.aws.amazon.com/cloudformation/home?region=us-east-1#/stacks/quickcreate?stackName=StreamSecurity",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := quickCreateTemplateURL(tc.url)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("quickCreateTemplateURL() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("quickCreateTemplateURL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
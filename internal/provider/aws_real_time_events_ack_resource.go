@@ -6,9 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -44,6 +44,10 @@ type AWSRealTimeEventsAckResourceModel struct {
 	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
 	Region                   types.String `tfsdk:"region"`
 	StreamsecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
+	Status                   types.String `tfsdk:"status"`
+	LastEventTime            types.String `tfsdk:"last_event_time"`
+	ErrorMessage             types.String `tfsdk:"error_message"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AWSRealTimeEventsAckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -78,16 +82,36 @@ func (r *AWSRealTimeEventsAckResource) Schema(ctx context.Context, req resource.
 				},
 				// add validator to check if the cloud_account_id is a valid AWS account ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^(\d{12})$`), "The cloud account ID must be a 12-digit number."),
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
 				},
 			},
 			"streamsec_collection_token": schema.StringAttribute{
 				Description: "The collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"status": schema.StringAttribute{
+				Description: "The real-time events collection status of the region.",
+				Computed:    true,
+			},
+			"last_event_time": schema.StringAttribute{
+				Description: "When the last event of the region was received.",
+				Computed:    true,
+			},
+			"error_message": schema.StringAttribute{
+				Description: "The last collection error of the region, if any.",
+				Computed:    true,
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -121,6 +145,8 @@ func (r *AWSRealTimeEventsAckResource) Create(ctx context.Context, req resource.
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -140,7 +166,7 @@ func (r *AWSRealTimeEventsAckResource) Create(ctx context.Context, req resource.
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -154,14 +180,9 @@ func (r *AWSRealTimeEventsAckResource) Create(ctx context.Context, req resource.
 
 		account := acc.(map[string]interface{})
 		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			if account["realtime_regions"] != nil {
-				regions := account["realtime_regions"].([]interface{})
-				for _, region := range regions {
-					if region.(map[string]interface{})["region_name"].(string) == data.Region.ValueString() {
-						resp.Diagnostics.AddError("Region already exists", "The specified region is already enabled for real-time events. Please import using terraform import.")
-						return
-					}
-				}
+			if realtimeRegion(account, data.Region.ValueString()) != nil {
+				resp.Diagnostics.AddError("Region already exists", "The specified region is already enabled for real-time events. Please import using terraform import.")
+				return
 			}
 			data.ID = types.StringValue(account["_id"].(string))
 			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
@@ -187,19 +208,17 @@ func (r *AWSRealTimeEventsAckResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/collection/cloudtrail/cft-event", r.client.Host)
+	url := fmt.Sprintf("https://%s/api/v1/collection/cloudtrail/cft-event", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("X-Lightlytics-Token", data.StreamsecCollectionToken.ValueString())
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
@@ -207,11 +226,18 @@ func (r *AWSRealTimeEventsAckResource) Create(ctx context.Context, req resource.
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", client.ResponseError(ack)))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
+	data.Status = types.StringNull()
+	data.LastEventTime = types.StringNull()
+	data.ErrorMessage = types.StringNull()
+	if account, err := getRealtimeEventsAccount(ctx, client, data.CloudAccountID.ValueString()); err == nil && account != nil {
+		if region := realtimeRegion(account, data.Region.ValueString()); region != nil {
+			r.readRegion(region, &data)
+		}
+	}
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -231,63 +257,31 @@ func (r *AWSRealTimeEventsAckResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				template_url
-				external_id
-				lightlytics_collection_token
-				account_auth_token
-				realtime_regions {
-					region_name
-				}
-			}
-		}`
+	client := workspaceClient(r.client, data.WorkspaceID)
 
-	res, err := r.client.DoRequest(query, nil)
+	account, err := getRealtimeEventsAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
-	regionFound := false
-
-	for _, acc := range accounts {
-
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			if account["realtime_regions"] != nil {
-				regions := account["realtime_regions"].([]interface{})
-				tflog.Debug(ctx, fmt.Sprintf("Regions: %v", regions))
-				for _, region := range regions {
-					if region.(map[string]interface{})["region_name"].(string) == data.Region.ValueString() {
-						regionFound = true
-					}
-				}
-			}
-			if !regionFound {
-				resp.State.RemoveResource(ctx)
-				return
-			}
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			accountFound = true
-		}
+	if account == nil {
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
-	if !accountFound {
+	// A region that is missing or was disabled from the UI is removed so the plan recreates it
+	region := realtimeRegion(account, data.Region.ValueString())
+	if region == nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+	r.readRegion(region, &data)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -317,6 +311,8 @@ func (r *AWSRealTimeEventsAckResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	body := CFTEventRequestBody{
 		AccountId:       data.CloudAccountID.ValueString(),
 		Region:          data.Region.ValueString(),
@@ -330,19 +326,17 @@ func (r *AWSRealTimeEventsAckResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/v1/collection/cloudtrail/cft-event", r.client.Host)
+	url := fmt.Sprintf("https://%s/api/v1/collection/cloudtrail/cft-event", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("X-Lightlytics-Token", data.StreamsecCollectionToken.ValueString())
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
@@ -350,7 +344,7 @@ func (r *AWSRealTimeEventsAckResource) Delete(ctx context.Context, req resource.
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", client.ResponseError(ack)))
 		return
 	}
 }
@@ -368,3 +362,9 @@ func (r *AWSRealTimeEventsAckResource) ImportState(ctx context.Context, req reso
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cloud_account_id"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), idParts[1])...)
 }
+
+func (r *AWSRealTimeEventsAckResource) readRegion(region map[string]interface{}, data *AWSRealTimeEventsAckResourceModel) {
+	data.Status = utils.ConvertInterfaceToTypesString(region["status"])
+	data.LastEventTime = utils.ConvertInterfaceToTypesString(region["last_event_time"])
+	data.ErrorMessage = utils.ConvertInterfaceToTypesString(region["error"])
+}
@@ -0,0 +1,485 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AWSRealTimeEventsResource{}
+var _ resource.ResourceWithImportState = &AWSRealTimeEventsResource{}
+
+func NewAWSRealTimeEventsResource() resource.Resource {
+	return &AWSRealTimeEventsResource{}
+}
+
+type AWSRealTimeEventsResource struct {
+	client *client.Client
+}
+type AWSRealTimeEventsResourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
+	Regions                  types.Set    `tfsdk:"regions"`
+	RegionStatus             types.Map    `tfsdk:"region_status"`
+	StreamsecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
+}
+
+func (r *AWSRealTimeEventsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_real_time_events"
+}
+
+func (r *AWSRealTimeEventsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Enables real-time events collection in a set of regions of an AWS account. " +
+			"Changing `regions` only enables the added regions and disables the removed ones.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The internal ID of the account.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cloud_account_id": schema.StringAttribute{
+				Description: "The cloud account ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
+				},
+			},
+			"regions": schema.SetAttribute{
+				Description: "The regions to collect real-time events from.",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(utils.AWSRegionRegex, "Each region must be a valid AWS region, e.g. us-east-1."),
+					),
+				},
+			},
+			"region_status": schema.MapAttribute{
+				Description: "The real-time events collection status of each region, as reported by Stream.Security.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"streamsec_collection_token": schema.StringAttribute{
+				Description: "The collection token.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AWSRealTimeEventsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AWSRealTimeEventsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AWSRealTimeEventsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRealtimeEventsAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	if account == nil {
+		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
+		return
+	}
+
+	enabled := realtimeRegionStatus(account)
+	var existing []string
+	for _, region := range utils.ConvertToStringSlice(data.Regions.Elements()) {
+		if _, ok := enabled[region]; ok {
+			existing = append(existing, region)
+		}
+	}
+	if len(existing) > 0 {
+		sort.Strings(existing)
+		resp.Diagnostics.AddError("Region already exists", fmt.Sprintf("Real-time events are already enabled in %s. Please import using terraform import.", strings.Join(existing, ", ")))
+		return
+	}
+
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+
+	added := sortedRegions(data.Regions)
+	applied, err := r.applyDelta(ctx, client, data, nil, added, nil)
+	data.Regions = regionSet(applied)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable real-time events, got error: %s", err))
+		if len(applied) == 0 {
+			return
+		}
+	}
+
+	r.setRegionStatus(ctx, client, &data)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state, including the regions enabled before a failure
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRealTimeEventsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AWSRealTimeEventsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRealtimeEventsAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	if account == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Keep the managed regions that are still enabled, so regions disabled outside of Terraform
+	// show as drift and regions enabled by other resources are left alone. An import adopts
+	// all the enabled regions.
+	status := realtimeRegionStatus(account)
+	regionStatus := map[string]attr.Value{}
+	regions := []string{}
+	for region, value := range status {
+		if data.Regions.IsNull() || data.Regions.IsUnknown() || slices.Contains(sortedRegions(data.Regions), region) {
+			regions = append(regions, region)
+			regionStatus[region] = value
+		}
+	}
+
+	if len(regions) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	sort.Strings(regions)
+
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+	data.Regions = regionSet(regions)
+	data.RegionStatus = types.MapValueMust(types.StringType, regionStatus)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRealTimeEventsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AWSRealTimeEventsResourceModel
+	var state AWSRealTimeEventsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	planned := map[string]bool{}
+	for _, region := range sortedRegions(data.Regions) {
+		planned[region] = true
+	}
+	current := map[string]bool{}
+	for _, region := range sortedRegions(state.Regions) {
+		current[region] = true
+	}
+
+	var added, removed []string
+	for _, region := range sortedRegions(data.Regions) {
+		if !current[region] {
+			added = append(added, region)
+		}
+	}
+	for _, region := range sortedRegions(state.Regions) {
+		if !planned[region] {
+			removed = append(removed, region)
+		}
+	}
+
+	applied, err := r.applyDelta(ctx, client, data, sortedRegions(state.Regions), added, removed)
+	data.Regions = regionSet(applied)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update real-time events regions, got error: %s", err))
+	}
+
+	r.setRegionStatus(ctx, client, &data)
+
+	// Save updated data into Terraform state, including the changes applied before a failure
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRealTimeEventsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AWSRealTimeEventsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	regions := sortedRegions(data.Regions)
+	applied, err := r.applyDelta(ctx, client, data, regions, nil, regions)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable real-time events, got error: %s", err))
+		// Keep the regions that are still enabled in state
+		data.Regions = regionSet(applied)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+}
+
+func (r *AWSRealTimeEventsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("cloud_account_id"), req, resp)
+}
+
+// applyDelta disables the removed regions and enables the added ones, returning the regions
+// that are enabled afterwards. On error it returns the regions enabled so far.
+func (r *AWSRealTimeEventsResource) applyDelta(ctx context.Context, client *client.Client, data AWSRealTimeEventsResourceModel, current, added, removed []string) ([]string, error) {
+	enabled := map[string]bool{}
+	for _, region := range current {
+		enabled[region] = true
+	}
+
+	result := func() []string {
+		regions := make([]string, 0, len(enabled))
+		for region := range enabled {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		return regions
+	}
+
+	for _, region := range removed {
+		if err := r.sendCFTEvent(ctx, client, data, region, "Delete"); err != nil {
+			return result(), fmt.Errorf("region %s: %w", region, err)
+		}
+		delete(enabled, region)
+	}
+
+	for _, region := range added {
+		if err := r.sendCFTEvent(ctx, client, data, region, "Create"); err != nil {
+			return result(), fmt.Errorf("region %s: %w", region, err)
+		}
+		enabled[region] = true
+	}
+
+	return result(), nil
+}
+
+func (r *AWSRealTimeEventsResource) sendCFTEvent(ctx context.Context, client *client.Client, data AWSRealTimeEventsResourceModel, region, operation string) error {
+	body := CFTEventRequestBody{
+		AccountId:       data.CloudAccountID.ValueString(),
+		Region:          region,
+		TemplateVersion: "1",
+		Operaion:        operation,
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://%s/api/v1/collection/cloudtrail/cft-event", client.Host)
+
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	ackReq.Header.Set("X-Lightlytics-Token", data.StreamsecCollectionToken.ValueString())
+
+	ack, err := client.Do(ackReq)
+	if err != nil {
+		return err
+	}
+	defer ack.Body.Close()
+
+	if ack.StatusCode != 200 {
+		return client.ResponseError(ack)
+	}
+
+	return nil
+}
+
+// getRealtimeEventsAccount returns the account with its real-time events regions, or nil when
+// the account does not exist.
+func getRealtimeEventsAccount(ctx context.Context, client *client.Client, cloudAccountID string) (map[string]interface{}, error) {
+	query := `
+		query {
+			accounts {
+				_id
+				cloud_account_id
+				lightlytics_collection_token
+				realtime_regions {
+					region_name
+					status
+					last_event_time
+					error
+				}
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range res["accounts"].([]interface{}) {
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"].(string) == cloudAccountID {
+			return account, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// setRegionStatus reads back the status of the regions after a change. Regions that are not
+// reported yet have an empty status.
+func (r *AWSRealTimeEventsResource) setRegionStatus(ctx context.Context, client *client.Client, data *AWSRealTimeEventsResourceModel) {
+	status := map[string]attr.Value{}
+	account, err := getRealtimeEventsAccount(ctx, client, data.CloudAccountID.ValueString())
+	if err == nil && account != nil {
+		status = realtimeRegionStatus(account)
+	}
+
+	regionStatus := map[string]attr.Value{}
+	for _, region := range sortedRegions(data.Regions) {
+		regionStatus[region] = types.StringValue("")
+		if value, ok := status[region]; ok {
+			regionStatus[region] = value
+		}
+	}
+	data.RegionStatus = types.MapValueMust(types.StringType, regionStatus)
+}
+
+// realtimeRegionDisabledStatus is reported for a region whose real-time events were turned off,
+// e.g. from the Stream.Security UI.
+const realtimeRegionDisabledStatus = "DISABLED"
+
+// realtimeRegion returns the real-time events details of a region of an account, or nil when
+// the region is not enabled.
+func realtimeRegion(account map[string]interface{}, name string) map[string]interface{} {
+	regions, _ := account["realtime_regions"].([]interface{})
+	for _, item := range regions {
+		region := item.(map[string]interface{})
+		if region["region_name"] == name && region["status"] != realtimeRegionDisabledStatus {
+			return region
+		}
+	}
+	return nil
+}
+
+// realtimeRegionStatus returns the status of each enabled region with real-time events of an account.
+func realtimeRegionStatus(account map[string]interface{}) map[string]attr.Value {
+	status := map[string]attr.Value{}
+	regions, _ := account["realtime_regions"].([]interface{})
+	for _, item := range regions {
+		region := item.(map[string]interface{})
+		name, _ := region["region_name"].(string)
+		value, _ := region["status"].(string)
+		if value == realtimeRegionDisabledStatus {
+			continue
+		}
+		status[name] = types.StringValue(value)
+	}
+	return status
+}
+
+func sortedRegions(regions types.Set) []string {
+	result := utils.ConvertToStringSlice(regions.Elements())
+	sort.Strings(result)
+	return result
+}
+
+func regionSet(regions []string) types.Set {
+	elements := make([]attr.Value, len(regions))
+	for i, region := range regions {
+		elements[i] = types.StringValue(region)
+	}
+	return types.SetValueMust(types.StringType, elements)
+}
@@ -0,0 +1,455 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AWSRemediationRunbookResource{}
+var _ resource.ResourceWithImportState = &AWSRemediationRunbookResource{}
+var _ resource.ResourceWithModifyPlan = &AWSRemediationRunbookResource{}
+
+func NewAWSRemediationRunbookResource() resource.Resource {
+	return &AWSRemediationRunbookResource{}
+}
+
+type RemediationRunbookRequestBody struct {
+	RunbookID string `json:"runbook_id"`
+	RoleARN   string `json:"role_arn"`
+}
+
+type AWSRemediationRunbookResource struct {
+	client *client.Client
+}
+type AWSRemediationRunbookResourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
+	Runbook                  types.String `tfsdk:"runbook"`
+	RoleARN                  types.String `tfsdk:"role_arn"`
+	StreamsecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
+}
+
+func (r *AWSRemediationRunbookResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_remediation_runbook"
+}
+
+func (r *AWSRemediationRunbookResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Enables a single remediation runbook on an AWS account whose remediation is already enabled. " +
+			"Enable the remediation with a `streamsec_aws_response_ack` whose `manage_runbooks` is `false`, otherwise it " +
+			"reports the runbooks of this resource as drift and disables them on its next update.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The internal ID of the account.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cloud_account_id": schema.StringAttribute{
+				Description: "The cloud account ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
+				},
+			},
+			"runbook": schema.StringAttribute{
+				Description: "The runbook ID, see the streamsec_runbooks data source for the available runbooks.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role_arn": schema.StringAttribute{
+				Description: "The ARN of the role the runbook runs with.",
+				Required:    true,
+			},
+			"streamsec_collection_token": schema.StringAttribute{
+				Description: "The collection token.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AWSRemediationRunbookResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data AWSRemediationRunbookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.WorkspaceID.IsUnknown() || data.Runbook.IsUnknown() {
+		return
+	}
+
+	// Only validate new runbooks, so runbooks retired from the catalog don't block unrelated plans
+	if !req.State.Raw.IsNull() {
+		var state AWSRemediationRunbookResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() || state.Runbook.Equal(data.Runbook) {
+			return
+		}
+	}
+
+	client, diags := runbookCatalogClient(ctx, r.client, data.WorkspaceID)
+	resp.Diagnostics.Append(diags...)
+
+	if client == nil {
+		return
+	}
+
+	runbooks := []plannedRunbook{{id: data.Runbook.ValueString(), path: path.Root("runbook")}}
+
+	resp.Diagnostics.Append(validateRunbooks(ctx, client, "aws", runbooks, types.ListNull(types.StringType))...)
+}
+
+func (r *AWSRemediationRunbookResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *AWSRemediationRunbookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AWSRemediationRunbookResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	if account == nil {
+		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
+		return
+	}
+
+	remediation, _ := account["remediation"].(map[string]interface{})
+	if status, _ := remediation["status"].(string); status != remediationReadyStatus {
+		resp.Diagnostics.AddError(
+			"Remediation not enabled",
+			fmt.Sprintf("Remediation of account %s is not ready (status %q), enable it with streamsec_aws_response_ack first.", data.CloudAccountID.ValueString(), status),
+		)
+		return
+	}
+
+	if remediationRunbookEnabled(remediation, data.Runbook.ValueString()) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Runbook %s is already enabled on account %s", data.Runbook.ValueString(), data.CloudAccountID.ValueString()))
+		return
+	}
+
+	resp.Diagnostics.Append(setRunbookAccount(&data, account)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s/runbooks", client.Host, data.CloudAccountID.ValueString())
+
+	if err := r.sendRunbookRequest(ctx, client, "POST", url, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable runbook, got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRemediationRunbookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AWSRemediationRunbookResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	remediation, _ := account["remediation"].(map[string]interface{})
+	if account == nil || !remediationRunbookEnabled(remediation, data.Runbook.ValueString()) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(setRunbookAccount(&data, account)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if roleARN := remediationRunbookRole(remediation, data.Runbook.ValueString()); roleARN != "" {
+		data.RoleARN = types.StringValue(roleARN)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRemediationRunbookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AWSRemediationRunbookResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s/runbooks/%s", client.Host, data.CloudAccountID.ValueString(), data.Runbook.ValueString())
+
+	if err := r.sendRunbookRequest(ctx, client, "PUT", url, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update runbook, got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "updated a resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AWSRemediationRunbookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AWSRemediationRunbookResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s/runbooks/%s", client.Host, data.CloudAccountID.ValueString(), data.Runbook.ValueString())
+
+	ackReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable runbook, got error: %s", err))
+		return
+	}
+
+	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
+
+	ack, err := client.Do(ackReq)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable runbook, got error: %s", err))
+		return
+	}
+	defer ack.Body.Close()
+
+	// The runbook is already gone when remediation was disabled for the whole account
+	if ack.StatusCode != 200 && ack.StatusCode != 404 {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to disable runbook, got error: %s", client.ResponseError(ack)))
+		return
+	}
+}
+
+func (r *AWSRemediationRunbookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: account_id,runbook. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cloud_account_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("runbook"), idParts[1])...)
+}
+
+// sendRunbookRequest sends the runbook and its role to url with the account collection token.
+func (r *AWSRemediationRunbookResource) sendRunbookRequest(ctx context.Context, client *client.Client, method string, url string, data AWSRemediationRunbookResourceModel) error {
+	jsonData, err := json.Marshal(RemediationRunbookRequestBody{
+		RunbookID: data.Runbook.ValueString(),
+		RoleARN:   data.RoleARN.ValueString(),
+	})
+	if err != nil {
+		return err
+	}
+
+	ackReq, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(jsonData))
+
+	if err != nil {
+		return err
+	}
+
+	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
+	ackReq.Header.Set("Content-Type", "application/json")
+
+	ack, err := client.Do(ackReq)
+
+	if err != nil {
+		return err
+	}
+	defer ack.Body.Close()
+
+	if ack.StatusCode != 200 {
+		return client.ResponseError(ack)
+	}
+
+	return nil
+}
+
+// getRemediationAccount returns the account with its remediation configuration, or nil if it does not exist.
+func getRemediationAccount(ctx context.Context, client *client.Client, cloudAccountID string) (map[string]interface{}, error) {
+	query := `
+		query {
+			accounts {
+				_id
+				cloud_account_id
+				lightlytics_collection_token
+				remediation {
+					status
+					status_message
+					role_arn
+					stack_id
+					external_id
+					runbook_list
+					runbook_role_list
+					policy_to_role_map
+					runbooks {
+						runbook_id
+						role_arn
+					}
+				}
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range res["accounts"].([]interface{}) {
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"].(string) == cloudAccountID {
+			return account, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// remediationRunbookEnabled reports whether the runbook is in the runbook list of the remediation.
+func remediationRunbookEnabled(remediation map[string]interface{}, runbook string) bool {
+	runbooks, _ := remediation["runbook_list"].([]interface{})
+	return slices.Contains(runbooks, interface{}(runbook))
+}
+
+// remediationRunbookRole returns the role ARN the runbook was enabled with. Runbooks without a role
+// assignment run with the role of the remediation.
+func remediationRunbookRole(remediation map[string]interface{}, runbook string) string {
+	runbooks, _ := remediation["runbooks"].([]interface{})
+	for _, value := range runbooks {
+		assignment, _ := value.(map[string]interface{})
+		if assignment["runbook_id"] == runbook {
+			if roleARN, _ := assignment["role_arn"].(string); roleARN != "" {
+				return roleARN
+			}
+		}
+	}
+	roleARN, _ := remediation["role_arn"].(string)
+	return roleARN
+}
+
+// setRunbookAccount copies the ID and collection token of the account, which the runbook requests
+// authenticate with.
+func setRunbookAccount(data *AWSRemediationRunbookResourceModel, account map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	id, idOK := account["_id"].(string)
+	token, tokenOK := account["lightlytics_collection_token"].(string)
+
+	if !idOK || !tokenOK {
+		diags.AddError("Unexpected API Response", fmt.Sprintf("Account %s has no ID or collection token in Stream.Security API.", data.CloudAccountID.ValueString()))
+		return diags
+	}
+
+	data.ID = types.StringValue(id)
+	data.StreamsecCollectionToken = types.StringValue(token)
+
+	return diags
+}
@@ -4,16 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
 	"terraform-provider-streamsec/internal/utils"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -24,6 +28,27 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AWSResponseAckResource{}
 var _ resource.ResourceWithImportState = &AWSResponseAckResource{}
+var _ resource.ResourceWithValidateConfig = &AWSResponseAckResource{}
+var _ resource.ResourceWithModifyPlan = &AWSResponseAckResource{}
+
+const (
+	defaultRemediationCreateTimeout = 20 * time.Minute
+	remediationPollInterval         = 15 * time.Second
+)
+
+// remediationReadyStatus is the remediation status of accounts whose remediation stack is deployed.
+const remediationReadyStatus = "READY"
+
+// Remediation statuses that are not transitional: the stack failed to deploy, or remediation
+// was disabled. An empty status is reported until the stack starts deploying.
+var (
+	remediationFailedStatuses   = map[string]bool{"FAILED": true, "ERROR": true}
+	remediationDisabledStatuses = map[string]bool{"DISABLED": true}
+)
+
+// errRemediationTimeout is returned by waitForRemediationReady when the remediation is still
+// transitional once the timeout expires.
+var errRemediationTimeout = errors.New("timed out")
 
 func NewAWSResponseAckResource() resource.Resource {
 	return &AWSResponseAckResource{}
@@ -45,15 +70,20 @@ type AWSResponseAckResource struct {
 	client *client.Client
 }
 type AWSResponseAckResourceModel struct {
-	ID                       types.String `tfsdk:"id"`
-	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
-	Region                   types.String `tfsdk:"region"`
-	StreamsecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
-	RoleARN                  types.String `tfsdk:"role_arn"`
-	RunbookList              types.List   `tfsdk:"runbook_list"`
-	RunbookRoleList          types.List   `tfsdk:"runbook_role_list"`
-	PolicyToRoleMap          types.Map    `tfsdk:"policy_to_role_map"`
-	ExternalId               types.String `tfsdk:"external_id"`
+	ID                       types.String   `tfsdk:"id"`
+	CloudAccountID           types.String   `tfsdk:"cloud_account_id"`
+	Region                   types.String   `tfsdk:"region"`
+	StreamsecCollectionToken types.String   `tfsdk:"streamsec_collection_token"`
+	RoleARN                  types.String   `tfsdk:"role_arn"`
+	RunbookList              types.List     `tfsdk:"runbook_list"`
+	RunbookRoleList          types.List     `tfsdk:"runbook_role_list"`
+	PolicyToRoleMap          types.Map      `tfsdk:"policy_to_role_map"`
+	ManageRunbooks           types.Bool     `tfsdk:"manage_runbooks"`
+	ExternalId               types.String   `tfsdk:"external_id"`
+	Status                   types.String   `tfsdk:"status"`
+	StatusMessage            types.String   `tfsdk:"status_message"`
+	WorkspaceID              types.String   `tfsdk:"workspace_id"`
+	Timeouts                 timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *AWSResponseAckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,7 +93,8 @@ func (r *AWSResponseAckResource) Metadata(ctx context.Context, req resource.Meta
 func (r *AWSResponseAckResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "AWSResponseAck resource",
+		MarkdownDescription: "AWSResponseAck resource. To enable runbooks one by one with `streamsec_aws_remediation_runbook`, " +
+			"set `manage_runbooks` to `false` so this resource leaves the runbooks alone.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -88,7 +119,7 @@ func (r *AWSResponseAckResource) Schema(ctx context.Context, req resource.Schema
 				},
 				// add validator to check if the cloud_account_id is a valid AWS account ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^(\d{12})$`), "The cloud account ID must be a 12-digit number."),
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The cloud account ID must be a 12-digit number."),
 				},
 			},
 			"role_arn": schema.StringAttribute{
@@ -100,34 +131,151 @@ func (r *AWSResponseAckResource) Schema(ctx context.Context, req resource.Schema
 			},
 			"runbook_list": schema.ListAttribute{
 				ElementType: types.StringType,
-				Description: "The runbook list.",
-				Required:    true,
+				Description: "The runbook list, see the streamsec_runbooks data source for the available runbooks. Required unless manage_runbooks is false.",
+				Optional:    true,
 			},
 			"runbook_role_list": schema.ListAttribute{
 				ElementType: types.StringType,
-				Description: "The runbook role list.",
-				Required:    true,
+				Description: "The runbook role list, must include the role of every runbook in runbook_list. Required unless manage_runbooks is false.",
+				Optional:    true,
 			},
 			"policy_to_role_map": schema.MapAttribute{
 				ElementType: types.StringType,
-				Description: "The policy to role map.",
-				Required:    true,
+				Description: "The policy to role map, its roles must be in runbook_role_list. Required unless manage_runbooks is false.",
+				Optional:    true,
+			},
+			"manage_runbooks": schema.BoolAttribute{
+				Description: "Whether this resource manages the runbooks of the account. When false, runbook_list, runbook_role_list and " +
+					"policy_to_role_map must not be set, and the runbooks enabled with streamsec_aws_remediation_runbook are kept. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
 			},
 			"external_id": schema.StringAttribute{
 				Description: "The external ID.",
 				Required:    true,
 			},
+			"status": schema.StringAttribute{
+				Description: "The remediation status, e.g. PENDING, DEPLOYING or READY.",
+				Computed:    true,
+			},
+			"status_message": schema.StringAttribute{
+				Description: "Details of the remediation status, e.g. why the stack failed to deploy.",
+				Computed:    true,
+			},
 			"streamsec_collection_token": schema.StringAttribute{
 				Description: "The collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+			}),
 		},
 	}
 }
 
+func (r *AWSResponseAckResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AWSResponseAckResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.ManageRunbooks.IsUnknown() {
+		return
+	}
+
+	runbookAttributes := []struct {
+		name  string
+		value attr.Value
+	}{
+		{"runbook_list", data.RunbookList},
+		{"runbook_role_list", data.RunbookRoleList},
+		{"policy_to_role_map", data.PolicyToRoleMap},
+	}
+
+	for _, attribute := range runbookAttributes {
+		switch {
+		case managesRunbooks(data) && attribute.value.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute.name),
+				"Missing Attribute Configuration",
+				fmt.Sprintf("%s is required unless manage_runbooks is false.", attribute.name),
+			)
+		case !managesRunbooks(data) && !attribute.value.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute.name),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("%s cannot be set when manage_runbooks is false, enable the runbooks with streamsec_aws_remediation_runbook.", attribute.name),
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() || data.RunbookRoleList.IsUnknown() || data.PolicyToRoleMap.IsUnknown() {
+		return
+	}
+
+	roles := map[string]bool{}
+	for _, role := range knownStrings(data.RunbookRoleList.Elements()) {
+		roles[role] = true
+	}
+
+	for policy, value := range data.PolicyToRoleMap.Elements() {
+		role, ok := value.(types.String)
+		if ok && !role.IsUnknown() && !role.IsNull() && !roles[role.ValueString()] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("policy_to_role_map").AtMapKey(policy),
+				"Unknown Runbook Role",
+				fmt.Sprintf("Policy %s is mapped to role %s, which is missing from runbook_role_list.", policy, role.ValueString()),
+			)
+		}
+	}
+}
+
+func (r *AWSResponseAckResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data AWSResponseAckResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.WorkspaceID.IsUnknown() {
+		return
+	}
+
+	// Only validate runbooks that change, so runbooks retired from the catalog don't block unrelated plans
+	if !req.State.Raw.IsNull() {
+		var state AWSResponseAckResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() || (state.RunbookList.Equal(data.RunbookList) && state.RunbookRoleList.Equal(data.RunbookRoleList)) {
+			return
+		}
+	}
+
+	client, diags := runbookCatalogClient(ctx, r.client, data.WorkspaceID)
+	resp.Diagnostics.Append(diags...)
+
+	if client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(validateRunbooks(ctx, client, "aws", listRunbooks(data.RunbookList, path.Root("runbook_list")), data.RunbookRoleList)...)
+}
+
 func (r *AWSResponseAckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -157,106 +305,68 @@ func (r *AWSResponseAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				stack_region
-				template_url
-				lightlytics_collection_token
-				account_auth_token
-				remediation {
-					status
-					role_arn
-					stack_id
-					runbook_list
-					runbook_role_list
-					policy_to_role_map
-					external_id
-				}
-			}
-		}`
-
-	res, err := r.client.DoRequest(query, nil)
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultRemediationCreateTimeout)
+	resp.Diagnostics.Append(diags...)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
+	client := workspaceClient(r.client, data.WorkspaceID)
 
-	for _, acc := range accounts {
+	account, err := getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
 
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			if account["remediation"] != nil && account["remediation"].(map[string]interface{})["status"] != nil {
-				if account["remediation"].(map[string]interface{})["status"].(string) == "READY" {
-					resp.Diagnostics.AddError("Client Error", "Account remediation is already enabled")
-					return
-				}
-			}
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			accountFound = true
-		}
-	}
-
-	if !accountFound {
-		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	body := RemediationRequestBody{
-		AccountId:       data.CloudAccountID.ValueString(),
-		Region:          data.Region.ValueString(),
-		TemplateVersion: "1",
-		ExternalId:      data.ExternalId.ValueString(),
-		RoleARN:         data.RoleARN.ValueString(),
-		StackId:         "terraform",
-		RunbookList:     utils.ConvertToStringSlice(data.RunbookList.Elements()),
-		RunbookRoleList: utils.ConvertToStringSlice(data.RunbookRoleList.Elements()),
-		PolicyToRoleMap: utils.ConvertToStringMap(data.PolicyToRoleMap.Elements()),
-	}
-
-	jsonData, err := json.Marshal(body)
-	if err != nil {
-		fmt.Printf("Error marshalling JSON: %s\n", err)
+	if account == nil {
+		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation-acknowledge", r.client.Host)
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+
+	// Adopt a remediation that is already enabled with the same configuration, e.g. one enabled
+	// before the resource was removed from state
+	remediation, _ := account["remediation"].(map[string]interface{})
+	if status, _ := remediation["status"].(string); status == remediationReadyStatus {
+		existing := data
+		setRemediationConfiguration(&existing, remediation)
+		if !remediationConfigurationEqual(existing, data) {
+			resp.Diagnostics.AddError("Client Error", "Account remediation is already enabled with a different configuration")
+			return
+		}
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
-	ackReq.Header.Set("Content-Type", "application/json")
+		setRemediationStatus(&data, remediation)
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
+		tflog.Trace(ctx, "adopted a resource")
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
-
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
+	if err := sendRemediationRequest(ctx, client, data, remediation); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to enable remediation, got error: %s", err))
 		return
 	}
 
-	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+	remediation, err = waitForRemediationReady(ctx, client, data.CloudAccountID.ValueString(), createTimeout)
+
+	setRemediationStatus(&data, remediation)
+
+	// The stack may still be deploying, keep the resource so the status is refreshed on the next plan
+	if errors.Is(err, errRemediationTimeout) {
+		resp.Diagnostics.AddWarning("Remediation not ready", fmt.Sprintf("Remediation of account %s is not ready yet, got error: %s", data.CloudAccountID.ValueString(), err))
+	} else if err != nil {
+		resp.Diagnostics.AddError("Remediation not ready", fmt.Sprintf("Remediation of account %s did not become ready, got error: %s", data.CloudAccountID.ValueString(), err))
+		// Keep the resource in state so the next apply picks it up instead of enabling it again
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -275,177 +385,79 @@ func (r *AWSResponseAckResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				template_url
-				lightlytics_collection_token
-				account_auth_token
-				remediation {
-					status
-					role_arn
-					stack_id
-					external_id
-					runbook_list
-					runbook_role_list
-					policy_to_role_map
-				}
-			}
-		}`
-
-	res, err := r.client.DoRequest(query, nil)
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
-
-	for _, acc := range accounts {
-
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			if account["remediation"] != nil {
-				if account["remediation"].(map[string]interface{})["status"].(string) == "READY" {
-					accountFound = true
-				}
-			}
-			if !accountFound {
-				resp.State.RemoveResource(ctx)
-				return
-			}
-
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			data.RoleARN = types.StringValue(account["remediation"].(map[string]interface{})["role_arn"].(string))
-			data.ExternalId = types.StringValue(account["remediation"].(map[string]interface{})["external_id"].(string))
-			data.RunbookList = utils.ConvertInterfaceToTypesList((account["remediation"].(map[string]interface{})["runbook_list"].([]interface{})))
-			data.RunbookRoleList = utils.ConvertInterfaceToTypesList((account["remediation"].(map[string]interface{})["runbook_role_list"].([]interface{})))
-			data.PolicyToRoleMap = utils.ConvertInterfaceToTypesMap((account["remediation"].(map[string]interface{})["policy_to_role_map"].(map[string]interface{})))
-
-			accountFound = true
-		}
-	}
+	remediation, _ := account["remediation"].(map[string]interface{})
+	status, _ := remediation["status"].(string)
 
-	if !accountFound {
+	if account == nil || remediation == nil || remediationDisabledStatuses[status] {
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
+	data.ManageRunbooks = types.BoolValue(managesRunbooks(data))
+	setRemediationStatus(&data, remediation)
+
+	// The configuration is only complete once the stack is deployed, keep the planned one until then
+	if status == remediationReadyStatus {
+		setRemediationConfiguration(&data, remediation)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AWSResponseAckResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data AWSResponseAckResourceModel
-	var state AWSResponseAckResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	query := `
-		query {
-			accounts {
-				_id
-				account_type
-				cloud_account_id
-				display_name
-				cloud_regions
-				stack_region
-				template_url
-				lightlytics_collection_token
-				account_auth_token
-				remediation {
-					status
-					role_arn
-					stack_id
-					runbook_list
-					runbook_role_list
-					policy_to_role_map
-					external_id
-				}
-			}
-		}`
-
-	res, err := r.client.DoRequest(query, nil)
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	account, err := getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	accounts := res["accounts"].([]interface{})
-	accountFound := false
-
-	for _, acc := range accounts {
-		account := acc.(map[string]interface{})
-		if account["cloud_account_id"].(string) == data.CloudAccountID.ValueString() {
-			data.ID = types.StringValue(account["_id"].(string))
-			data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
-			accountFound = true
-		}
-	}
-
-	if !accountFound {
+	if account == nil {
 		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
 		return
 	}
 
-	body := RemediationRequestBody{
-		AccountId:       data.CloudAccountID.ValueString(),
-		Region:          data.Region.ValueString(),
-		TemplateVersion: "1",
-		ExternalId:      data.ExternalId.ValueString(),
-		RoleARN:         data.RoleARN.ValueString(),
-		StackId:         "terraform",
-		RunbookList:     utils.ConvertToStringSlice(data.RunbookList.Elements()),
-		RunbookRoleList: utils.ConvertToStringSlice(data.RunbookRoleList.Elements()),
-		PolicyToRoleMap: utils.ConvertToStringMap(data.PolicyToRoleMap.Elements()),
-	}
-
-	jsonData, err := json.Marshal(body)
-	if err != nil {
-		fmt.Printf("Error marshalling JSON: %s\n", err)
-		return
-	}
-
-	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation-acknowledge", r.client.Host)
-
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
-	ackReq.Header.Set("Content-Type", "application/json")
+	data.ID = types.StringValue(account["_id"].(string))
+	data.StreamsecCollectionToken = types.StringValue(account["lightlytics_collection_token"].(string))
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
+	remediation, _ := account["remediation"].(map[string]interface{})
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
+	if err := sendRemediationRequest(ctx, client, data, remediation); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update remediation, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	account, err = getRemediationAccount(ctx, client, data.CloudAccountID.ValueString())
 
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
-		return
-	}
-
-	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
+	remediation, _ = account["remediation"].(map[string]interface{})
+	setRemediationStatus(&data, remediation)
 
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "updated a resource")
@@ -464,9 +476,11 @@ func (r *AWSResponseAckResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s", r.client.Host, data.CloudAccountID.ValueString())
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s", client.Host, data.CloudAccountID.ValueString())
 
-	ackReq, err := http.NewRequest("DELETE", url, nil)
+	ackReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
 
 	if err != nil {
@@ -474,7 +488,7 @@ func (r *AWSResponseAckResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", err))
@@ -482,7 +496,7 @@ func (r *AWSResponseAckResource) Delete(ctx context.Context, req resource.Delete
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", client.ResponseError(ack)))
 		return
 	}
 }
@@ -490,3 +504,142 @@ func (r *AWSResponseAckResource) Delete(ctx context.Context, req resource.Delete
 func (r *AWSResponseAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("cloud_account_id"), req, resp)
 }
+
+// sendRemediationRequest acknowledges the remediation stack with the planned configuration. When
+// the runbooks are not managed, the runbooks of the current remediation are sent back unchanged.
+func sendRemediationRequest(ctx context.Context, client *client.Client, data AWSResponseAckResourceModel, remediation map[string]interface{}) error {
+	if !managesRunbooks(data) {
+		current := AWSResponseAckResourceModel{ManageRunbooks: types.BoolValue(true)}
+		setRemediationConfiguration(&current, remediation)
+		data.RunbookList = current.RunbookList
+		data.RunbookRoleList = current.RunbookRoleList
+		data.PolicyToRoleMap = current.PolicyToRoleMap
+	}
+
+	body := RemediationRequestBody{
+		AccountId:       data.CloudAccountID.ValueString(),
+		Region:          data.Region.ValueString(),
+		TemplateVersion: "1",
+		ExternalId:      data.ExternalId.ValueString(),
+		RoleARN:         data.RoleARN.ValueString(),
+		StackId:         "terraform",
+		RunbookList:     utils.ConvertToStringSlice(data.RunbookList.Elements()),
+		RunbookRoleList: utils.ConvertToStringSlice(data.RunbookRoleList.Elements()),
+		PolicyToRoleMap: utils.ConvertToStringMap(data.PolicyToRoleMap.Elements()),
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation-acknowledge", client.Host)
+
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+
+	if err != nil {
+		return err
+	}
+
+	ackReq.Header.Set("Authorization", "Bearer "+data.StreamsecCollectionToken.ValueString())
+	ackReq.Header.Set("Content-Type", "application/json")
+
+	ack, err := client.Do(ackReq)
+
+	if err != nil {
+		return err
+	}
+	defer ack.Body.Close()
+
+	if ack.StatusCode != 200 {
+		return client.ResponseError(ack)
+	}
+
+	return nil
+}
+
+// waitForRemediationReady polls the remediation of the account until it is ready, failed or
+// the timeout expires, returning the last remediation read.
+func waitForRemediationReady(ctx context.Context, client *client.Client, cloudAccountID string, timeout time.Duration) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(remediationPollInterval)
+	defer ticker.Stop()
+
+	var remediation map[string]interface{}
+
+	for {
+		account, err := getRemediationAccount(ctx, client, cloudAccountID)
+
+		if err != nil && ctx.Err() == nil {
+			return remediation, err
+		}
+
+		if account != nil {
+			remediation, _ = account["remediation"].(map[string]interface{})
+		}
+
+		status, _ := remediation["status"].(string)
+		message, _ := remediation["status_message"].(string)
+
+		if status == remediationReadyStatus {
+			return remediation, nil
+		}
+
+		if remediationFailedStatuses[status] {
+			return remediation, fmt.Errorf("status %s: %s", status, message)
+		}
+
+		tflog.Debug(ctx, "waiting for remediation", map[string]interface{}{"status": status})
+
+		select {
+		case <-ctx.Done():
+			return remediation, fmt.Errorf("%w after %s in status %q", errRemediationTimeout, timeout, status)
+		case <-ticker.C:
+		}
+	}
+}
+
+// setRemediationStatus copies the remediation status, empty when nothing was reported yet.
+func setRemediationStatus(data *AWSResponseAckResourceModel, remediation map[string]interface{}) {
+	status, _ := remediation["status"].(string)
+	message, _ := remediation["status_message"].(string)
+	data.Status = types.StringValue(status)
+	data.StatusMessage = types.StringValue(message)
+}
+
+// setRemediationConfiguration reads back the remediation configuration, without the runbooks
+// when they are not managed.
+func setRemediationConfiguration(data *AWSResponseAckResourceModel, remediation map[string]interface{}) {
+	data.RoleARN = utils.ConvertInterfaceToTypesString(remediation["role_arn"])
+	data.ExternalId = utils.ConvertInterfaceToTypesString(remediation["external_id"])
+
+	if !managesRunbooks(*data) {
+		return
+	}
+
+	data.RunbookList = utils.ConvertOptionalInterfaceToTypesList(remediation["runbook_list"])
+	data.RunbookRoleList = utils.ConvertOptionalInterfaceToTypesList(remediation["runbook_role_list"])
+
+	policyToRoleMap, _ := remediation["policy_to_role_map"].(map[string]interface{})
+	if policyToRoleMap == nil {
+		policyToRoleMap = map[string]interface{}{}
+	}
+	data.PolicyToRoleMap = utils.ConvertInterfaceToTypesMap(policyToRoleMap)
+}
+
+// remediationConfigurationEqual reports whether two remediation configurations match.
+func remediationConfigurationEqual(a AWSResponseAckResourceModel, b AWSResponseAckResourceModel) bool {
+	return a.RoleARN.Equal(b.RoleARN) &&
+		a.ExternalId.Equal(b.ExternalId) &&
+		a.RunbookList.Equal(b.RunbookList) &&
+		a.RunbookRoleList.Equal(b.RunbookRoleList) &&
+		a.PolicyToRoleMap.Equal(b.PolicyToRoleMap)
+}
+
+// managesRunbooks reports whether the response ack manages the runbooks, true for imported states
+// and states written before manage_runbooks existed.
+func managesRunbooks(data AWSResponseAckResourceModel) bool {
+	return data.ManageRunbooks.IsNull() || data.ManageRunbooks.ValueBool()
+}
@@ -0,0 +1,268 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+// trustedAccountIDRegex matches the account ID of a principal, given alone or as an ARN.
+var trustedAccountIDRegex = regexp.MustCompile(`(?:^|:)(\d{12})(?::|$)`)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AWSTrustPolicyDataSource{}
+
+func NewAWSTrustPolicyDataSource() datasource.DataSource {
+	return &AWSTrustPolicyDataSource{}
+}
+
+// AWSTrustPolicyDataSource defines the data source implementation.
+type AWSTrustPolicyDataSource struct {
+	client *client.Client
+}
+
+// AWSTrustPolicyDataSourceModel describes the data source data model.
+type AWSTrustPolicyDataSourceModel struct {
+	ExternalID       types.String `tfsdk:"external_id"`
+	TrustedAccountID types.String `tfsdk:"trusted_account_id"`
+	Partition        types.String `tfsdk:"partition"`
+	JSON             types.String `tfsdk:"json"`
+	WorkspaceID      types.String `tfsdk:"workspace_id"`
+}
+
+func (d *AWSTrustPolicyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_trust_policy"
+}
+
+func (d *AWSTrustPolicyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The assume role policy of the IAM role Stream.Security uses, for roles created with Terraform " +
+			"instead of the onboarding CloudFormation template.",
+
+		Attributes: map[string]schema.Attribute{
+			"external_id": schema.StringAttribute{
+				MarkdownDescription: "The external ID of the account, from `streamsec_aws_account.external_id`.",
+				Required:            true,
+			},
+			"trusted_account_id": schema.StringAttribute{
+				MarkdownDescription: "The AWS account Stream.Security assumes the role from. Defaults to the account trusted by the " +
+					"`account` template Stream.Security currently deploys.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(utils.AWSAccountIDRegex, "The trusted account ID must be a 12-digit number."),
+				},
+			},
+			"partition": schema.StringAttribute{
+				MarkdownDescription: "The AWS partition of the role. Defaults to `aws`.",
+				Optional:            true,
+			},
+			"json": schema.StringAttribute{
+				MarkdownDescription: "The trust policy document, for `aws_iam_role.assume_role_policy`.",
+				Computed:            true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (d *AWSTrustPolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AWSTrustPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AWSTrustPolicyDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.TrustedAccountID.IsNull() {
+		client := workspaceClient(d.client, data.WorkspaceID)
+
+		_, body, diags := getAWSTemplateBody(ctx, client, "account", "")
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		accountID, err := templateTrustedAccountID(body)
+
+		if err != nil {
+			resp.Diagnostics.AddError("Template Error", fmt.Sprintf("Unable to read the trusted account of the account template, set trusted_account_id instead, got error: %s", err))
+			return
+		}
+
+		data.TrustedAccountID = types.StringValue(accountID)
+	}
+
+	trustedAccountID := data.TrustedAccountID.ValueString()
+
+	partition := "aws"
+	if !data.Partition.IsNull() {
+		partition = data.Partition.ValueString()
+	}
+
+	document := utils.NewIAMPolicyDocument(utils.IAMPolicyStatement{
+		Effect: "Allow",
+		Principal: map[string]interface{}{
+			"AWS": fmt.Sprintf("arn:%s:iam::%s:root", partition, trustedAccountID),
+		},
+		Action: []string{"sts:AssumeRole"},
+		Condition: map[string]map[string]string{
+			"StringEquals": {
+				"sts:ExternalId": data.ExternalID.ValueString(),
+			},
+		},
+	})
+
+	policy, err := document.JSON()
+
+	if err != nil {
+		resp.Diagnostics.AddError("Policy Error", fmt.Sprintf("Unable to render trust policy, got error: %s", err))
+		return
+	}
+
+	data.JSON = types.StringValue(policy)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// templateTrustedAccountID returns the AWS account trusted by the assume role policies of the roles of
+// a CloudFormation template. Principals referencing a parameter resolve to the parameter default.
+func templateTrustedAccountID(body string) (string, error) {
+	var template struct {
+		Parameters map[string]struct {
+			Default string `yaml:"Default"`
+		} `yaml:"Parameters"`
+		Resources map[string]struct {
+			Type       string `yaml:"Type"`
+			Properties struct {
+				AssumeRolePolicyDocument struct {
+					Statement yaml.Node `yaml:"Statement"`
+				} `yaml:"AssumeRolePolicyDocument"`
+			} `yaml:"Properties"`
+		} `yaml:"Resources"`
+	}
+
+	if err := yaml.Unmarshal([]byte(body), &template); err != nil {
+		return "", err
+	}
+
+	parameters := map[string]string{}
+	for name, parameter := range template.Parameters {
+		parameters[name] = parameter.Default
+	}
+
+	accountIDs := []string{}
+	for name, resource := range template.Resources {
+		if resource.Type != "AWS::IAM::Role" {
+			continue
+		}
+
+		// A single statement may be given without a list
+		document := &resource.Properties.AssumeRolePolicyDocument
+		statementNodes := []*yaml.Node{&document.Statement}
+		if document.Statement.Kind == yaml.SequenceNode {
+			statementNodes = document.Statement.Content
+		}
+
+		for _, statementNode := range statementNodes {
+			var statement struct {
+				Principal struct {
+					AWS yaml.Node `yaml:"AWS"`
+				} `yaml:"Principal"`
+			}
+
+			if statementNode.Kind != yaml.MappingNode {
+				continue
+			}
+
+			if err := statementNode.Decode(&statement); err != nil {
+				return "", fmt.Errorf("resource %s: %w", name, err)
+			}
+
+			for _, principal := range principalStrings(&statement.Principal.AWS, parameters) {
+				match := trustedAccountIDRegex.FindStringSubmatch(principal)
+				if match != nil && !slices.Contains(accountIDs, match[1]) {
+					accountIDs = append(accountIDs, match[1])
+				}
+			}
+		}
+	}
+
+	if len(accountIDs) != 1 {
+		return "", fmt.Errorf("expected one trusted account, found %d", len(accountIDs))
+	}
+
+	return accountIDs[0], nil
+}
+
+// principalStrings returns the strings of a principal node, which may be a list or use intrinsic
+// functions such as !Sub. References to parameters resolve to their defaults.
+func principalStrings(node *yaml.Node, parameters map[string]string) []string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!Ref" {
+			return []string{parameters[node.Value]}
+		}
+		return []string{node.Value}
+	case yaml.MappingNode:
+		if len(node.Content) == 2 && node.Content[0].Value == "Ref" {
+			return []string{parameters[node.Content[1].Value]}
+		}
+		values := []string{}
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, principalStrings(node.Content[i], parameters)...)
+		}
+		return values
+	case yaml.SequenceNode:
+		values := []string{}
+		for _, n := range node.Content {
+			values = append(values, principalStrings(n, parameters)...)
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package provider
+
+import "testing"
+
+func TestTemplateTrustedAccountID(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "sub arn",
+			template: `
+Resources:
+  Role:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Statement:
+          - Effect: Allow
+            Principal:
+              AWS: !Sub "arn:${AWS::Partition}:iam::123456789012:root"
+            Action: sts:AssumeRole
+`,
+			want: "123456789012",
+		},
+		{
+			name: "parameter default in JSON",
+			template: `{
+  "Parameters": {"TrustedAccount": {"Type": "String", "Default": "123456789012"}},
+  "Resources": {
+    "Role": {
+      "Type": "AWS::IAM::Role",
+      "Properties": {
+        "AssumeRolePolicyDocument": {
+          "Statement": {
+            "Effect": "Allow",
+            "Principal": {"AWS": [{"Ref": "TrustedAccount"}]},
+            "Action": "sts:AssumeRole"
+          }
+        }
+      }
+    }
+  }
+}`,
+			want: "123456789012",
+		},
+		{
+			name: "several roles trusting the same account",
+			template: `
+Resources:
+  Role:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Statement:
+          - Principal:
+              AWS: "123456789012"
+  Other:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Statement:
+          - Principal:
+              AWS: arn:aws:iam::123456789012:root
+`,
+			want: "123456789012",
+		},
+		{
+			name: "service principal only",
+			template: `
+Resources:
+  Role:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Statement:
+          - Principal:
+              Service: lambda.amazonaws.com
+`,
+			wantErr: true,
+		},
+		{
+			name: "several trusted accounts",
+			template: `
+Resources:
+  Role:
+    Type: AWS::IAM::Role
+    Properties:
+      AssumeRolePolicyDocument:
+        Statement:
+          - Principal:
+              AWS:
+                - arn:aws:iam::123456789012:root
+                - arn:aws:iam::210987654321:root
+`,
+			wantErr: true,
+		},
+		{
+			name:     "invalid",
+			template: "Resources: [",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := templateTrustedAccountID(tc.template)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("templateTrustedAccountID() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("templateTrustedAccountID() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
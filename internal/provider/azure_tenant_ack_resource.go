@@ -6,20 +6,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 	"terraform-provider-streamsec/internal/client"
 	"terraform-provider-streamsec/internal/utils"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -27,6 +29,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AzureTenantAckResource{}
 var _ resource.ResourceWithImportState = &AzureTenantAckResource{}
+var _ resource.ResourceWithConfigValidators = &AzureTenantAckResource{}
 
 func NewAzureTenantAckResource() resource.Resource {
 	return &AzureTenantAckResource{}
@@ -36,12 +39,16 @@ type AzureTenantAckResource struct {
 	client *client.Client
 }
 type AzureTenantAckResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	CloudAccountID types.String `tfsdk:"tenant_id"`
-	ClientID       types.String `tfsdk:"client_id"`
-	ClientSecret   types.String `tfsdk:"client_secret"`
-	Subscriptions  types.List   `tfsdk:"subscriptions"`
-	AccountToken   types.String `tfsdk:"account_token"`
+	ID                    types.String `tfsdk:"id"`
+	CloudAccountID        types.String `tfsdk:"tenant_id"`
+	ClientID              types.String `tfsdk:"client_id"`
+	ClientSecret          types.String `tfsdk:"client_secret"`
+	ClientSecretWO        types.String `tfsdk:"client_secret_wo"`
+	ClientSecretWOVersion types.Int64  `tfsdk:"client_secret_wo_version"`
+	ClientSecretFile      types.String `tfsdk:"client_secret_file"`
+	Subscriptions         types.List   `tfsdk:"subscriptions"`
+	AccountToken          types.String `tfsdk:"account_token"`
+	WorkspaceID           types.String `tfsdk:"workspace_id"`
 }
 
 type AzureAckRequestBody struct {
@@ -73,20 +80,34 @@ func (r *AzureTenantAckResource) Schema(ctx context.Context, req resource.Schema
 				Description: "The Azure tenant ID.",
 				Required:    true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z-]{36}$`), "Azure tenant ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
+					stringvalidator.RegexMatches(utils.AzureIDRegex, "Azure tenant ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
 				},
 			},
 			"client_id": schema.StringAttribute{
 				Description: "The client ID.",
 				Required:    true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z-]{36}$`), "Client ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
+					stringvalidator.RegexMatches(utils.AzureIDRegex, "Client ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
 				},
 			},
 			"client_secret": schema.StringAttribute{
-				Description: "The client secret.",
-				Required:    true,
+				Description: "The client secret. Exactly one of client_secret, client_secret_wo or client_secret_file must be set.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"client_secret_wo": schema.StringAttribute{
+				Description: "The client secret as a write-only argument, never stored in state. Requires Terraform 1.11 or later.",
+				Optional:    true,
 				Sensitive:   true,
+				WriteOnly:   true,
+			},
+			"client_secret_wo_version": schema.Int64Attribute{
+				Description: "Change this value to send a new client_secret_wo or re-read client_secret_file.",
+				Optional:    true,
+			},
+			"client_secret_file": schema.StringAttribute{
+				Description: "Path to a file containing the client secret, e.g. rendered by Vault Agent or SOPS.",
+				Optional:    true,
 			},
 			"subscriptions": schema.ListAttribute{
 				ElementType: types.StringType,
@@ -95,7 +116,7 @@ func (r *AzureTenantAckResource) Schema(ctx context.Context, req resource.Schema
 				Validators: []validator.List{
 					listvalidator.All(
 						listvalidator.ValueStringsAre(
-							stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z-]{36}$`), "Subscription ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
+							stringvalidator.RegexMatches(utils.AzureIDRegex, "Subscription ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
 						),
 					),
 				},
@@ -103,14 +124,36 @@ func (r *AzureTenantAckResource) Schema(ctx context.Context, req resource.Schema
 			"account_token": schema.StringAttribute{
 				Description: "The collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *AzureTenantAckResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("client_secret"),
+			path.MatchRoot("client_secret_wo"),
+			path.MatchRoot("client_secret_file"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("client_secret_wo"),
+			path.MatchRoot("client_secret_wo_version"),
+		),
+	}
+}
+
 func (r *AzureTenantAckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -140,6 +183,8 @@ func (r *AzureTenantAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -154,7 +199,7 @@ func (r *AzureTenantAckResource) Create(ctx context.Context, req resource.Create
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -179,13 +224,18 @@ func (r *AzureTenantAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Tenant found: %v", data))
+	clientSecret, diags := r.clientSecret(ctx, req.Config, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	body := AzureAckRequestBody{
 		AccountType:   "Azure",
 		TenantID:      data.CloudAccountID.ValueString(),
 		ClientID:      data.ClientID.ValueString(),
-		ClientSecret:  data.ClientSecret.ValueString(),
+		ClientSecret:  clientSecret,
 		Subscriptions: strings.Join(utils.ConvertToStringSlice(data.Subscriptions.Elements()), ","),
 	}
 
@@ -195,19 +245,17 @@ func (r *AzureTenantAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/azure/account-acknowledge", r.client.Host)
+	url := fmt.Sprintf("https://%s/azure/account-acknowledge", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", data.AccountToken.ValueString()))
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
@@ -215,12 +263,10 @@ func (r *AzureTenantAckResource) Create(ctx context.Context, req resource.Create
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", client.ResponseError(ack)))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -239,6 +285,8 @@ func (r *AzureTenantAckResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -252,7 +300,7 @@ func (r *AzureTenantAckResource) Read(ctx context.Context, req resource.ReadRequ
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -304,8 +352,43 @@ func (r *AzureTenantAckResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	credentialsChanged := data.ClientID != state.ClientID || data.ClientSecret != state.ClientSecret ||
+		data.ClientSecretFile != state.ClientSecretFile || data.ClientSecretWOVersion != state.ClientSecretWOVersion
+
 	// check if there was a change in display_name
-	if !utils.EqualListValues(data.Subscriptions, state.Subscriptions) || data.ClientID != state.ClientID || data.ClientSecret != state.ClientSecret {
+	if !utils.EqualListValues(data.Subscriptions, state.Subscriptions) || credentialsChanged {
+		clientSecret, diags := r.clientSecret(ctx, req.Config, data)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// verify the new credentials before replacing the active ones
+		if credentialsChanged {
+			check, err := client.ValidateCredentials(ctx, "azure", data.AccountToken.ValueString(), AzureAckRequestBody{
+				AccountType:   "Azure",
+				TenantID:      data.CloudAccountID.ValueString(),
+				ClientID:      data.ClientID.ValueString(),
+				ClientSecret:  clientSecret,
+				Subscriptions: strings.Join(utils.ConvertToStringSlice(data.Subscriptions.Elements()), ","),
+			})
+
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to validate credentials, got error: %s", err))
+				return
+			}
+
+			if !check.Valid {
+				resp.Diagnostics.AddAttributeError(path.Root("client_secret"), "Credential Validation Failed",
+					"The new credentials failed the Stream.Security connection check, the previous credentials remain active.\n\n"+check.Summary())
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+				return
+			}
+		}
+
 		query := `
 			mutation UpdateAccount($id: ID!, $account: AccountUpdateInput) {
 				updateAccount(id: $id, account: $account) {
@@ -318,13 +401,11 @@ func (r *AzureTenantAckResource) Update(ctx context.Context, req resource.Update
 			"account": map[string]interface{}{
 				"subscriptions": utils.ConvertToStringSlice(data.Subscriptions.Elements()),
 				"client_id":     data.ClientID.ValueString(),
-				"client_secret": data.ClientSecret.ValueString(),
+				"client_secret": clientSecret,
 			},
 		}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -348,6 +429,26 @@ func (r *AzureTenantAckResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
+// clientSecret resolves the client secret from client_secret, client_secret_wo or client_secret_file.
+func (r *AzureTenantAckResource) clientSecret(ctx context.Context, config tfsdk.Config, data AzureTenantAckResourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var clientSecretWO types.String
+
+	diags.Append(config.GetAttribute(ctx, path.Root("client_secret_wo"), &clientSecretWO)...)
+
+	if diags.HasError() {
+		return "", diags
+	}
+
+	clientSecret, err := utils.ResolveSecret(data.ClientSecret, clientSecretWO, data.ClientSecretFile)
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("client_secret"), "Invalid Client Secret", fmt.Sprintf("Unable to resolve the client secret, got error: %s", err))
+	}
+
+	return clientSecret, diags
+}
+
 func (r *AzureTenantAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("tenant_id"), req, resp)
 }
@@ -6,8 +6,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -35,6 +35,7 @@ type AzureTenantDataSourceModel struct {
 	CloudAccountID types.String `tfsdk:"tenant_id"`
 	DisplayName    types.String `tfsdk:"display_name"`
 	AccountToken   types.String `tfsdk:"account_token"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
 }
 
 func (d *AzureTenantDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -55,7 +56,7 @@ func (d *AzureTenantDataSource) Schema(ctx context.Context, req datasource.Schem
 				MarkdownDescription: "The Azure tenant ID.",
 				Required:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-z-]{36}$`), "Azure tenant ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
+					stringvalidator.RegexMatches(utils.AzureIDRegex, "Azure tenant ID must be a 36-character string with lowercase letters, numbers, and hyphens."),
 				},
 			},
 			"display_name": schema.StringAttribute{
@@ -65,6 +66,11 @@ func (d *AzureTenantDataSource) Schema(ctx context.Context, req datasource.Schem
 			"account_token": schema.StringAttribute{
 				MarkdownDescription: "The account token of the tenant.",
 				Computed:            true,
+				Sensitive:           true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
 			},
 		},
 	}
@@ -100,6 +106,8 @@ func (d *AzureTenantDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
+	client := workspaceClient(d.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -115,7 +123,7 @@ func (d *AzureTenantDataSource) Read(ctx context.Context, req datasource.ReadReq
 			}
 		}`
 
-	res, err := d.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -125,8 +133,6 @@ func (d *AzureTenantDataSource) Read(ctx context.Context, req datasource.ReadReq
 	accounts := res["accounts"].([]interface{})
 	accountFound := false
 
-	tflog.Debug(ctx, fmt.Sprintf("accounts: %v", accounts))
-
 	for _, acc := range accounts {
 
 		account := acc.(map[string]interface{})
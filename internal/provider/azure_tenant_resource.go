@@ -30,6 +30,7 @@ type AzureTenantResourceModel struct {
 	CloudAccountID types.String `tfsdk:"tenant_id"`
 	DisplayName    types.String `tfsdk:"display_name"`
 	AccountToken   types.String `tfsdk:"account_token"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
 }
 
 func (r *AzureTenantResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,10 +64,18 @@ func (r *AzureTenantResource) Schema(ctx context.Context, req resource.SchemaReq
 			"account_token": schema.StringAttribute{
 				Description: "The account token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -100,6 +109,8 @@ func (r *AzureTenantResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation CreateAzurePortalAccount($account_type: CloudProvider!, $display_name: String!, $tenant_id: String!) {
 			createAccount(account: {
@@ -120,17 +131,13 @@ func (r *AzureTenantResource) Create(ctx context.Context, req resource.CreateReq
 		"tenant_id":    data.CloudAccountID.ValueString(),
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err := r.client.DoRequest(query, variables)
+	res, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	account := res["createAccount"].(map[string]interface{})
 	data.ID = types.StringValue(account["_id"].(string))
 	data.AccountToken = types.StringValue(account["account_token"].(string))
@@ -153,6 +160,8 @@ func (r *AzureTenantResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -168,7 +177,7 @@ func (r *AzureTenantResource) Read(ctx context.Context, req resource.ReadRequest
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -177,7 +186,6 @@ func (r *AzureTenantResource) Read(ctx context.Context, req resource.ReadRequest
 
 	accounts := res["accounts"].([]interface{})
 	accountFound := false
-	tflog.Debug(ctx, fmt.Sprintf("Accounts: %v", accounts))
 
 	for _, acc := range accounts {
 
@@ -214,6 +222,8 @@ func (r *AzureTenantResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
 	if data.DisplayName != state.DisplayName {
 		query := `
@@ -228,9 +238,7 @@ func (r *AzureTenantResource) Update(ctx context.Context, req resource.UpdateReq
 			"account": map[string]interface{}{
 				"display_name": data.DisplayName.ValueString()}}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -253,6 +261,8 @@ func (r *AzureTenantResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation DeleteAccount($id: ID!) {
 			deleteAccount(id: $id)
@@ -261,7 +271,7 @@ func (r *AzureTenantResource) Delete(ctx context.Context, req resource.DeleteReq
 	variables := map[string]interface{}{
 		"id": data.ID.ValueString()}
 
-	_, err := r.client.DoRequest(query, variables)
+	_, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete account, got error: %s", err))
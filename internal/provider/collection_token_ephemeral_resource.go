@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-streamsec/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &CollectionTokenEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CollectionTokenEphemeralResource{}
+
+func NewCollectionTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &CollectionTokenEphemeralResource{}
+}
+
+// CollectionTokenEphemeralResource defines the ephemeral resource implementation.
+type CollectionTokenEphemeralResource struct {
+	client *client.Client
+}
+
+// CollectionTokenEphemeralResourceModel describes the ephemeral resource data model.
+type CollectionTokenEphemeralResourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	CloudAccountID           types.String `tfsdk:"cloud_account_id"`
+	AccountType              types.String `tfsdk:"account_type"`
+	StreamSecCollectionToken types.String `tfsdk:"streamsec_collection_token"`
+	AccountAuthToken         types.String `tfsdk:"account_auth_token"`
+	AccountToken             types.String `tfsdk:"account_token"`
+	WorkspaceID              types.String `tfsdk:"workspace_id"`
+}
+
+func (e *CollectionTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collection_token"
+}
+
+func (e *CollectionTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Fetches the collection and auth tokens of an onboarded account at apply time without persisting them to state.",
+
+		Attributes: map[string]schema.Attribute{
+			"cloud_account_id": schema.StringAttribute{
+				MarkdownDescription: "The cloud account ID (AWS account ID, Azure tenant ID, GCP project ID or Google Workspace customer ID).",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The internal ID of the account.",
+				Computed:            true,
+			},
+			"account_type": schema.StringAttribute{
+				MarkdownDescription: "The account type.",
+				Computed:            true,
+			},
+			"streamsec_collection_token": schema.StringAttribute{
+				MarkdownDescription: "The Stream Security collection token of the account.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"account_auth_token": schema.StringAttribute{
+				MarkdownDescription: "The account auth token of the account.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"account_token": schema.StringAttribute{
+				MarkdownDescription: "The account token of the account.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (e *CollectionTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = client
+}
+
+func (e *CollectionTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data CollectionTokenEphemeralResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(e.client, data.WorkspaceID)
+
+	query := `
+		query {
+			accounts {
+				_id
+				account_type
+				cloud_account_id
+				lightlytics_collection_token
+				account_auth_token
+				account_token
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
+		return
+	}
+
+	accounts := res["accounts"].([]interface{})
+	accountFound := false
+
+	for _, acc := range accounts {
+
+		account := acc.(map[string]interface{})
+		if account["cloud_account_id"] == data.CloudAccountID.ValueString() {
+			data.ID = types.StringValue(account["_id"].(string))
+			data.AccountType = types.StringValue(account["account_type"].(string))
+			data.StreamSecCollectionToken = types.StringNull()
+			if token, ok := account["lightlytics_collection_token"].(string); ok {
+				data.StreamSecCollectionToken = types.StringValue(token)
+			}
+			data.AccountAuthToken = types.StringNull()
+			if token, ok := account["account_auth_token"].(string); ok {
+				data.AccountAuthToken = types.StringValue(token)
+			}
+			data.AccountToken = types.StringNull()
+			if token, ok := account["account_token"].(string); ok {
+				data.AccountToken = types.StringValue(token)
+			}
+			accountFound = true
+		}
+	}
+
+	if !accountFound {
+		resp.Diagnostics.AddError("Resource not found", fmt.Sprintf("Unable to get account, account with cloud_account_id: %s not found in Stream.Security API.", data.CloudAccountID.ValueString()))
+		return
+	}
+
+	tflog.Trace(ctx, "opened an ephemeral resource")
+
+	// Save data into ephemeral result data
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultProfile = "default"
+
+// errProfileNotFound is returned when the credentials file has no section for the profile.
+var errProfileNotFound = errors.New("profile not found")
+
+// credentialsProfile is a named section of the shared credentials file, e.g.
+//
+//	[staging]
+//	host         = staging.streamsec.io
+//	api_token    = xxxxxxxx
+//	workspace_id = 123456123213213123
+type credentialsProfile struct {
+	Host        string
+	ApiToken    string
+	Username    string
+	Password    string
+	WorkspaceId string
+}
+
+// defaultCredentialsFile returns ~/.streamsec/credentials.
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".streamsec", "credentials")
+}
+
+// loadCredentialsProfile reads the named profile from an INI formatted credentials file.
+func loadCredentialsProfile(path, name string) (*credentialsProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var profile *credentialsProfile
+	section := ""
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			if section == name && profile == nil {
+				profile = &credentialsProfile{}
+			}
+			continue
+		}
+		if section != name {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, lineNumber)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		switch strings.TrimSpace(key) {
+		case "host":
+			profile.Host = value
+		case "api_token":
+			profile.ApiToken = value
+		case "username":
+			profile.Username = value
+		case "password":
+			profile.Password = value
+		case "workspace_id":
+			profile.WorkspaceId = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown key %q in profile %s", path, lineNumber, strings.TrimSpace(key), name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("%w: %q in %s", errProfileNotFound, name, path)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		profile string
+		want    credentialsProfile
+		wantErr error
+	}{
+		{
+			name: "default profile",
+			file: `
+[default]
+host         = app.streamsec.io
+api_token    = token
+workspace_id = workspace
+`,
+			profile: "default",
+			want:    credentialsProfile{Host: "app.streamsec.io", ApiToken: "token", WorkspaceId: "workspace"},
+		},
+		{
+			name: "named profile",
+			file: `
+# shared credentials
+[default]
+api_token = token
+
+[profile staging]
+host     = "staging.streamsec.io"
+username = 'user'
+; the password of the staging user
+password = pass
+`,
+			profile: "staging",
+			want:    credentialsProfile{Host: "staging.streamsec.io", Username: "user", Password: "pass"},
+		},
+		{
+			name: "ignores errors in other profiles",
+			file: `
+[default]
+api_token = token
+
+[broken]
+not a key value
+`,
+			profile: "default",
+			want:    credentialsProfile{ApiToken: "token"},
+		},
+		{
+			name: "profile not found",
+			file: `
+[default]
+api_token = token
+`,
+			profile: "staging",
+			wantErr: errProfileNotFound,
+		},
+		{
+			name: "missing value",
+			file: `
+[default]
+api_token
+`,
+			profile: "default",
+			wantErr: errors.New("expected key = value"),
+		},
+		{
+			name: "unknown key",
+			file: `
+[default]
+token = token
+`,
+			profile: "default",
+			wantErr: errors.New(`unknown key "token"`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "credentials")
+			if err := os.WriteFile(path, []byte(tc.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadCredentialsProfile(path, tc.profile)
+
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatalf("loadCredentialsProfile() = %+v, want error %v", got, tc.wantErr)
+				}
+				if !errors.Is(err, tc.wantErr) && !strings.Contains(err.Error(), tc.wantErr.Error()) {
+					t.Errorf("loadCredentialsProfile() error = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadCredentialsProfile() error = %v", err)
+			}
+			if *got != tc.want {
+				t.Errorf("loadCredentialsProfile() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsProfileMissingFile(t *testing.T) {
+	_, err := loadCredentialsProfile(filepath.Join(t.TempDir(), "credentials"), defaultProfile)
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("loadCredentialsProfile() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
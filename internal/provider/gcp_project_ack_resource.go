@@ -7,12 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -20,6 +26,9 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &GCPProjectAckResource{}
 var _ resource.ResourceWithImportState = &GCPProjectAckResource{}
+var _ resource.ResourceWithConfigValidators = &GCPProjectAckResource{}
+var _ resource.ResourceWithValidateConfig = &GCPProjectAckResource{}
+var _ resource.ResourceWithModifyPlan = &GCPProjectAckResource{}
 
 func NewGCPProjectAckResource() resource.Resource {
 	return &GCPProjectAckResource{}
@@ -29,11 +38,17 @@ type GCPProjectAckResource struct {
 	client *client.Client
 }
 type GCPProjectAckResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	CloudAccountID types.String `tfsdk:"project_id"`
-	ClientEmail    types.String `tfsdk:"client_email"`
-	PrivateKey     types.String `tfsdk:"private_key"`
-	AccountToken   types.String `tfsdk:"account_token"`
+	ID                  types.String `tfsdk:"id"`
+	CloudAccountID      types.String `tfsdk:"project_id"`
+	ClientEmail         types.String `tfsdk:"client_email"`
+	PrivateKey          types.String `tfsdk:"private_key"`
+	PrivateKeyWO        types.String `tfsdk:"private_key_wo"`
+	PrivateKeyWOVersion types.Int64  `tfsdk:"private_key_wo_version"`
+	CredentialsFile     types.String `tfsdk:"credentials_file"`
+	CredentialsBase64   types.String `tfsdk:"credentials_base64"`
+	CredentialsJSON     types.String `tfsdk:"credentials_json"`
+	AccountToken        types.String `tfsdk:"account_token"`
+	WorkspaceID         types.String `tfsdk:"workspace_id"`
 }
 
 type GCPProjectAckRequestBody struct {
@@ -65,26 +80,134 @@ func (r *GCPProjectAckResource) Schema(ctx context.Context, req resource.SchemaR
 				Required:    true,
 			},
 			"client_email": schema.StringAttribute{
-				Description: "The service account email.",
-				Required:    true,
+				Description: "The service account email. Required with private_key or private_key_wo, read from the key when credentials_json, credentials_file or credentials_base64 is set.",
+				Optional:    true,
+				Computed:    true,
 				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"private_key": schema.StringAttribute{
-				Description: "The service account private key.",
-				Required:    true,
+				Description: "The service account private key. Exactly one of private_key, private_key_wo, credentials_json, credentials_file or credentials_base64 must be set.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_email")),
+				},
+			},
+			"private_key_wo": schema.StringAttribute{
+				Description: "The service account private key as a write-only argument, never stored in state. Requires Terraform 1.11 or later.",
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_email")),
+				},
+			},
+			"private_key_wo_version": schema.Int64Attribute{
+				Description: "Change this value to send a new private_key_wo or re-read credentials_file.",
+				Optional:    true,
+			},
+			"credentials_file": schema.StringAttribute{
+				Description: "Path to a service account JSON key file.",
+				Optional:    true,
+			},
+			"credentials_base64": schema.StringAttribute{
+				Description: "A base64 encoded service account JSON key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"credentials_json": schema.StringAttribute{
+				Description: "The service account JSON key, e.g. file(\"key.json\"). Conflicts with client_email and private_key.",
+				Optional:    true,
 				Sensitive:   true,
 			},
 			"account_token": schema.StringAttribute{
 				Description: "The collection token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *GCPProjectAckResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("private_key_wo"),
+			path.MatchRoot("credentials_file"),
+			path.MatchRoot("credentials_base64"),
+			path.MatchRoot("credentials_json"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("private_key_wo"),
+			path.MatchRoot("private_key_wo_version"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_file"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_base64"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_json"),
+		),
+	}
+}
+
+func (r *GCPProjectAckResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data GCPProjectAckResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(planClientEmail(ctx, data.CredentialsJSON, data.CredentialsFile, data.CredentialsBase64, &resp.Plan)...)
+}
+
+func (r *GCPProjectAckResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data GCPProjectAckResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.CredentialsJSON.IsNull() || data.CredentialsJSON.IsUnknown() {
+		return
+	}
+
+	key, err := utils.ParseGCPServiceAccountKey([]byte(data.CredentialsJSON.ValueString()))
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("credentials_json"), "Invalid Service Account Key", err.Error())
+		return
+	}
+
+	if key.ProjectID != "" && !data.CloudAccountID.IsNull() && !data.CloudAccountID.IsUnknown() && key.ProjectID != data.CloudAccountID.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("credentials_json"), "Service Account Project Mismatch", fmt.Sprintf("The service account key belongs to project %s, expected %s.", key.ProjectID, data.CloudAccountID.ValueString()))
+	}
+}
+
 func (r *GCPProjectAckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -114,6 +237,8 @@ func (r *GCPProjectAckResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -125,7 +250,7 @@ func (r *GCPProjectAckResource) Create(ctx context.Context, req resource.CreateR
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -150,13 +275,20 @@ func (r *GCPProjectAckResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("GCP Project found: %v", data))
+	key, diags := r.credentials(ctx, req.Config, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ClientEmail = types.StringValue(key.ClientEmail)
 
 	body := GCPProjectAckRequestBody{
 		AccountType: "GCP",
 		ProjectID:   data.CloudAccountID.ValueString(),
-		ClientEmail: data.ClientEmail.ValueString(),
-		PrivateKey:  data.PrivateKey.ValueString(),
+		ClientEmail: key.ClientEmail,
+		PrivateKey:  key.PrivateKey,
 	}
 
 	jsonData, err := json.Marshal(body)
@@ -165,19 +297,17 @@ func (r *GCPProjectAckResource) Create(ctx context.Context, req resource.CreateR
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/gcp/account-acknowledge", r.client.Host)
+	url := fmt.Sprintf("https://%s/gcp/account-acknowledge", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", data.AccountToken.ValueString()))
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send acknowledge to Stream Security, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send acknowledge to Stream Security, got error: %s", err))
@@ -185,12 +315,10 @@ func (r *GCPProjectAckResource) Create(ctx context.Context, req resource.CreateR
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send acknowledge to Stream Security, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to send acknowledge to Stream Security, got error: %s", client.ResponseError(ack)))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -209,6 +337,8 @@ func (r *GCPProjectAckResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -219,7 +349,7 @@ func (r *GCPProjectAckResource) Read(ctx context.Context, req resource.ReadReque
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -260,8 +390,40 @@ func (r *GCPProjectAckResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
-	if data.ClientEmail != state.ClientEmail || data.PrivateKey != state.PrivateKey {
+	if data.ClientEmail != state.ClientEmail || data.PrivateKey != state.PrivateKey || data.PrivateKeyWOVersion != state.PrivateKeyWOVersion ||
+		data.CredentialsFile != state.CredentialsFile || data.CredentialsBase64 != state.CredentialsBase64 || data.CredentialsJSON != state.CredentialsJSON {
+		key, diags := r.credentials(ctx, req.Config, data)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.ClientEmail = types.StringValue(key.ClientEmail)
+
+		// verify the new credentials before replacing the active ones
+		check, err := client.ValidateCredentials(ctx, "gcp", data.AccountToken.ValueString(), GCPProjectAckRequestBody{
+			AccountType: "GCP",
+			ProjectID:   data.CloudAccountID.ValueString(),
+			ClientEmail: key.ClientEmail,
+			PrivateKey:  key.PrivateKey,
+		})
+
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to validate credentials, got error: %s", err))
+			return
+		}
+
+		if !check.Valid {
+			resp.Diagnostics.AddAttributeError(path.Root("private_key"), "Credential Validation Failed",
+				"The new credentials failed the Stream.Security connection check, the previous credentials remain active.\n\n"+check.Summary())
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+
 		query := `
 			mutation UpdateAccount($id: ID!, $account: AccountUpdateInput) {
 				updateAccount(id: $id, account: $account) {
@@ -272,14 +434,12 @@ func (r *GCPProjectAckResource) Update(ctx context.Context, req resource.UpdateR
 		variables := map[string]interface{}{
 			"id": data.ID.ValueString(),
 			"account": map[string]interface{}{
-				"client_email": data.ClientEmail.ValueString(),
-				"private_key":  data.PrivateKey.ValueString(),
+				"client_email": key.ClientEmail,
+				"private_key":  key.PrivateKey,
 			},
 		}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err = client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -303,6 +463,54 @@ func (r *GCPProjectAckResource) Delete(ctx context.Context, req resource.DeleteR
 	}
 }
 
+// credentials resolves the service account key from the configured credential source.
+func (r *GCPProjectAckResource) credentials(ctx context.Context, config tfsdk.Config, data GCPProjectAckResourceModel) (*utils.GCPServiceAccountKey, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var privateKeyWO types.String
+
+	diags.Append(config.GetAttribute(ctx, path.Root("private_key_wo"), &privateKeyWO)...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	key, err := utils.ResolveGCPCredentials(data.ClientEmail, data.PrivateKey, privateKeyWO, data.CredentialsJSON, data.CredentialsFile, data.CredentialsBase64)
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("private_key"), "Invalid Service Account Credentials", fmt.Sprintf("Unable to resolve the service account credentials, got error: %s", err))
+		return nil, diags
+	}
+
+	if key.ProjectID != "" && key.ProjectID != data.CloudAccountID.ValueString() {
+		diags.AddAttributeError(path.Root("project_id"), "Service Account Project Mismatch", fmt.Sprintf("The service account key belongs to project %s, expected %s.", key.ProjectID, data.CloudAccountID.ValueString()))
+	}
+
+	return key, diags
+}
+
 func (r *GCPProjectAckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("project_id"), req, resp)
 }
+
+// planClientEmail plans client_email to the email of the service account JSON key, as it is
+// overwritten from the key on apply and the key may belong to another service account than the one
+// in state. It is unknown when the key cannot be read until apply.
+func planClientEmail(ctx context.Context, credentialsJSON, file, encoded types.String, plan *tfsdk.Plan) diag.Diagnostics {
+	if credentialsJSON.IsUnknown() || file.IsUnknown() || encoded.IsUnknown() {
+		return plan.SetAttribute(ctx, path.Root("client_email"), types.StringUnknown())
+	}
+
+	key, err := utils.LoadGCPServiceAccountKey(credentialsJSON, file, encoded)
+
+	// The email comes from the configuration when the key is not a JSON key
+	if key == nil && err == nil {
+		return nil
+	}
+
+	// Apply reports the error
+	if err != nil {
+		return plan.SetAttribute(ctx, path.Root("client_email"), types.StringUnknown())
+	}
+
+	return plan.SetAttribute(ctx, path.Root("client_email"), types.StringValue(key.ClientEmail))
+}
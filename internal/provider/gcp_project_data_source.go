@@ -32,6 +32,7 @@ type GCPProjectDataSourceModel struct {
 	CloudAccountID types.String `tfsdk:"project_id"`
 	DisplayName    types.String `tfsdk:"display_name"`
 	AccountToken   types.String `tfsdk:"account_token"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
 }
 
 func (d *GCPProjectDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -59,6 +60,11 @@ func (d *GCPProjectDataSource) Schema(ctx context.Context, req datasource.Schema
 			"account_token": schema.StringAttribute{
 				MarkdownDescription: "The account token of the project.",
 				Computed:            true,
+				Sensitive:           true,
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
 			},
 		},
 	}
@@ -94,6 +100,8 @@ func (d *GCPProjectDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	client := workspaceClient(d.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -106,7 +114,7 @@ func (d *GCPProjectDataSource) Read(ctx context.Context, req datasource.ReadRequ
 			}
 		}`
 
-	res, err := d.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -116,8 +124,6 @@ func (d *GCPProjectDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	accounts := res["accounts"].([]interface{})
 	accountFound := false
 
-	tflog.Debug(ctx, fmt.Sprintf("accounts: %v", accounts))
-
 	for _, acc := range accounts {
 
 		account := acc.(map[string]interface{})
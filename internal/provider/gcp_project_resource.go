@@ -30,6 +30,7 @@ type GCPProjectResourceModel struct {
 	DisplayName    types.String `tfsdk:"display_name"`
 	CloudAccountID types.String `tfsdk:"project_id"`
 	AccountToken   types.String `tfsdk:"account_token"`
+	WorkspaceID    types.String `tfsdk:"workspace_id"`
 }
 
 func (r *GCPProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,10 +64,18 @@ func (r *GCPProjectResource) Schema(ctx context.Context, req resource.SchemaRequ
 			"account_token": schema.StringAttribute{
 				Description: "The account token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -100,6 +109,8 @@ func (r *GCPProjectResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation CreateAccount($account_type: CloudProvider!, $cloud_account_id: String!, $display_name: String) {
 			createAccount(account: {
@@ -119,17 +130,13 @@ func (r *GCPProjectResource) Create(ctx context.Context, req resource.CreateRequ
 		"display_name":     data.DisplayName.ValueString(),
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err := r.client.DoRequest(query, variables)
+	res, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	account := res["createAccount"].(map[string]interface{})
 	data.ID = types.StringValue(account["_id"].(string))
 	data.AccountToken = types.StringValue(account["account_token"].(string))
@@ -152,6 +159,8 @@ func (r *GCPProjectResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -164,7 +173,7 @@ func (r *GCPProjectResource) Read(ctx context.Context, req resource.ReadRequest,
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -209,6 +218,8 @@ func (r *GCPProjectResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
 	if data.DisplayName != state.DisplayName {
 		query := `
@@ -223,9 +234,7 @@ func (r *GCPProjectResource) Update(ctx context.Context, req resource.UpdateRequ
 			"account": map[string]interface{}{
 				"display_name": data.DisplayName.ValueString()}}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -248,6 +257,8 @@ func (r *GCPProjectResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation DeleteAccount($id: ID!) {
 			deleteAccount(id: $id)
@@ -256,7 +267,7 @@ func (r *GCPProjectResource) Delete(ctx context.Context, req resource.DeleteRequ
 	variables := map[string]interface{}{
 		"id": data.ID.ValueString()}
 
-	_, err := r.client.DoRequest(query, variables)
+	_, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete account, got error: %s", err))
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"terraform-provider-streamsec/internal/client"
 	"terraform-provider-streamsec/internal/utils"
 
@@ -24,6 +23,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &GCPResponseAckResource{}
 var _ resource.ResourceWithImportState = &GCPResponseAckResource{}
+var _ resource.ResourceWithModifyPlan = &GCPResponseAckResource{}
 
 func NewGCPResponseAckResource() resource.Resource {
 	return &GCPResponseAckResource{}
@@ -46,6 +46,7 @@ type GCPResponseAckResourceModel struct {
 	TemplateVersion types.String `tfsdk:"template_version"`
 	RunbookList     types.List   `tfsdk:"runbook_list"`
 	Location        types.String `tfsdk:"location"`
+	WorkspaceID     types.String `tfsdk:"workspace_id"`
 }
 
 func (r *GCPResponseAckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -73,7 +74,7 @@ func (r *GCPResponseAckResource) Schema(ctx context.Context, req resource.Schema
 				},
 				// add validator to check if the cloud_account_id is a valid GCP project ID
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9][a-z0-9-]{4,28}[a-z0-9]$`), "The cloud account ID must be a valid GCP project ID."),
+					stringvalidator.RegexMatches(utils.GCPProjectIDRegex, "The cloud account ID must be a valid GCP project ID."),
 				},
 			},
 			"runbook_list": schema.ListAttribute{
@@ -92,14 +93,57 @@ func (r *GCPResponseAckResource) Schema(ctx context.Context, req resource.Schema
 			"account_token": schema.StringAttribute{
 				Description: "The account token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *GCPResponseAckResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to validate on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data GCPResponseAckResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.WorkspaceID.IsUnknown() {
+		return
+	}
+
+	// Only validate runbooks that change, so runbooks retired from the catalog don't block unrelated plans
+	if !req.State.Raw.IsNull() {
+		var state GCPResponseAckResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() || state.RunbookList.Equal(data.RunbookList) {
+			return
+		}
+	}
+
+	client, diags := runbookCatalogClient(ctx, r.client, data.WorkspaceID)
+	resp.Diagnostics.Append(diags...)
+
+	if client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(validateRunbooks(ctx, client, "gcp", listRunbooks(data.RunbookList, path.Root("runbook_list")), types.ListNull(types.StringType))...)
+}
+
 func (r *GCPResponseAckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -129,6 +173,8 @@ func (r *GCPResponseAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -143,7 +189,7 @@ func (r *GCPResponseAckResource) Create(ctx context.Context, req resource.Create
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -187,20 +233,18 @@ func (r *GCPResponseAckResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/gcp/remediation-acknowledge", r.client.Host)
+	url := fmt.Sprintf("https://%s/gcp/remediation-acknowledge", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("Authorization", "Bearer "+data.AccountToken.ValueString())
 	ackReq.Header.Set("Content-Type", "application/json")
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
@@ -208,12 +252,10 @@ func (r *GCPResponseAckResource) Create(ctx context.Context, req resource.Create
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", client.ResponseError(ack)))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -232,6 +274,8 @@ func (r *GCPResponseAckResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -247,7 +291,7 @@ func (r *GCPResponseAckResource) Read(ctx context.Context, req resource.ReadRequ
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -300,6 +344,8 @@ func (r *GCPResponseAckResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -314,7 +360,7 @@ func (r *GCPResponseAckResource) Update(ctx context.Context, req resource.Update
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -351,20 +397,18 @@ func (r *GCPResponseAckResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/gcp/remediation-acknowledge", r.client.Host)
+	url := fmt.Sprintf("https://%s/gcp/remediation-acknowledge", client.Host)
 
-	ackReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	ackReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	ackReq.Header.Set("Authorization", "Bearer "+data.AccountToken.ValueString())
 	ackReq.Header.Set("Content-Type", "application/json")
 
-	tflog.Debug(ctx, fmt.Sprintf("Request: %v", ackReq))
-
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ack region, got error: %s", err))
@@ -372,12 +416,10 @@ func (r *GCPResponseAckResource) Update(ctx context.Context, req resource.Update
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", client.ResponseError(ack)))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "updated a resource")
 
@@ -395,9 +437,11 @@ func (r *GCPResponseAckResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s", r.client.Host, data.CloudAccountID.ValueString())
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	url := fmt.Sprintf("https://%s/api/accounts/accounts/remediation/%s", client.Host, data.CloudAccountID.ValueString())
 
-	ackReq, err := http.NewRequest("DELETE", url, nil)
+	ackReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	ackReq.Header.Set("Authorization", "Bearer "+data.AccountToken.ValueString())
 
 	if err != nil {
@@ -405,7 +449,7 @@ func (r *GCPResponseAckResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	ack, err := http.DefaultClient.Do(ackReq)
+	ack, err := client.Do(ackReq)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", err))
@@ -413,7 +457,7 @@ func (r *GCPResponseAckResource) Delete(ctx context.Context, req resource.Delete
 	}
 
 	if ack.StatusCode != 200 {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", ack.Status))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete remediation, got error: %s", client.ResponseError(ack)))
 		return
 	}
 }
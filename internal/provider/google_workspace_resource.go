@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -17,6 +23,9 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &GoogleWorkspaceResource{}
 var _ resource.ResourceWithImportState = &GoogleWorkspaceResource{}
+var _ resource.ResourceWithConfigValidators = &GoogleWorkspaceResource{}
+var _ resource.ResourceWithValidateConfig = &GoogleWorkspaceResource{}
+var _ resource.ResourceWithModifyPlan = &GoogleWorkspaceResource{}
 
 func NewGoogleWorkspaceResource() resource.Resource {
 	return &GoogleWorkspaceResource{}
@@ -26,12 +35,18 @@ type GoogleWorkspaceResource struct {
 	client *client.Client
 }
 type GoogleWorkspaceResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	DisplayName    types.String `tfsdk:"display_name"`
-	CloudAccountID types.String `tfsdk:"customer_id"`
-	ClientEmail    types.String `tfsdk:"client_email"`
-	PrivateKey     types.String `tfsdk:"private_key"`
-	AccountToken   types.String `tfsdk:"account_token"`
+	ID                  types.String `tfsdk:"id"`
+	DisplayName         types.String `tfsdk:"display_name"`
+	CloudAccountID      types.String `tfsdk:"customer_id"`
+	ClientEmail         types.String `tfsdk:"client_email"`
+	PrivateKey          types.String `tfsdk:"private_key"`
+	PrivateKeyWO        types.String `tfsdk:"private_key_wo"`
+	PrivateKeyWOVersion types.Int64  `tfsdk:"private_key_wo_version"`
+	CredentialsFile     types.String `tfsdk:"credentials_file"`
+	CredentialsBase64   types.String `tfsdk:"credentials_base64"`
+	CredentialsJSON     types.String `tfsdk:"credentials_json"`
+	AccountToken        types.String `tfsdk:"account_token"`
+	WorkspaceID         types.String `tfsdk:"workspace_id"`
 }
 
 func (r *GoogleWorkspaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -63,25 +78,128 @@ func (r *GoogleWorkspaceResource) Schema(ctx context.Context, req resource.Schem
 				},
 			},
 			"client_email": schema.StringAttribute{
-				Description: "The service account email.",
-				Required:    true,
+				Description: "The service account email. Required with private_key or private_key_wo, read from the key when credentials_json, credentials_file or credentials_base64 is set.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"private_key": schema.StringAttribute{
-				Description: "The service account private key.",
-				Required:    true,
+				Description: "The service account private key. Exactly one of private_key, private_key_wo, credentials_json, credentials_file or credentials_base64 must be set.",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_email")),
+				},
+			},
+			"private_key_wo": schema.StringAttribute{
+				Description: "The service account private key as a write-only argument, never stored in state. Requires Terraform 1.11 or later.",
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_email")),
+				},
+			},
+			"private_key_wo_version": schema.Int64Attribute{
+				Description: "Change this value to send a new private_key_wo or re-read credentials_file.",
+				Optional:    true,
+			},
+			"credentials_file": schema.StringAttribute{
+				Description: "Path to a service account JSON key file.",
+				Optional:    true,
+			},
+			"credentials_base64": schema.StringAttribute{
+				Description: "A base64 encoded service account JSON key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"credentials_json": schema.StringAttribute{
+				Description: "The service account JSON key, e.g. file(\"key.json\"). Conflicts with client_email and private_key.",
+				Optional:    true,
 				Sensitive:   true,
 			},
 			"account_token": schema.StringAttribute{
 				Description: "The account token.",
 				Computed:    true,
+				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"workspace_id": schema.StringAttribute{
+				Description: "The workspace ID to manage this resource in, overrides the provider workspace_id.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
+func (r *GoogleWorkspaceResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("private_key"),
+			path.MatchRoot("private_key_wo"),
+			path.MatchRoot("credentials_file"),
+			path.MatchRoot("credentials_base64"),
+			path.MatchRoot("credentials_json"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("private_key_wo"),
+			path.MatchRoot("private_key_wo_version"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_file"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_base64"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("client_email"),
+			path.MatchRoot("credentials_json"),
+		),
+	}
+}
+
+func (r *GoogleWorkspaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data GoogleWorkspaceResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(planClientEmail(ctx, data.CredentialsJSON, data.CredentialsFile, data.CredentialsBase64, &resp.Plan)...)
+}
+
+func (r *GoogleWorkspaceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data GoogleWorkspaceResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.CredentialsJSON.IsNull() || data.CredentialsJSON.IsUnknown() {
+		return
+	}
+
+	_, err := utils.ParseGCPServiceAccountKey([]byte(data.CredentialsJSON.ValueString()))
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("credentials_json"), "Invalid Service Account Key", err.Error())
+	}
+}
+
 func (r *GoogleWorkspaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -111,6 +229,17 @@ func (r *GoogleWorkspaceResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
+	key, diags := r.credentials(ctx, req.Config, data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ClientEmail = types.StringValue(key.ClientEmail)
+
 	query := `
 		mutation CreateAccount($account_type: CloudProvider!, $cloud_account_id: String!, $display_name: String) {
 			createAccount(account: {
@@ -128,21 +257,17 @@ func (r *GoogleWorkspaceResource) Create(ctx context.Context, req resource.Creat
 		"account_type":     "GOOGLE_WORKSPACE",
 		"cloud_account_id": data.CloudAccountID.ValueString(),
 		"display_name":     data.DisplayName.ValueString(),
-		"client_email":     data.ClientEmail.ValueString(),
-		"private_key":      data.PrivateKey.ValueString(),
+		"client_email":     key.ClientEmail,
+		"private_key":      key.PrivateKey,
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-	res, err := r.client.DoRequest(query, variables)
+	res, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create account, got error: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Response: %v", res))
-
 	account := res["createAccount"].(map[string]interface{})
 	data.ID = types.StringValue(account["_id"].(string))
 	data.AccountToken = types.StringValue(account["account_token"].(string))
@@ -165,6 +290,8 @@ func (r *GoogleWorkspaceResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		query {
 			accounts {
@@ -177,7 +304,7 @@ func (r *GoogleWorkspaceResource) Read(ctx context.Context, req resource.ReadReq
 			}
 		}`
 
-	res, err := r.client.DoRequest(query, nil)
+	res, err := client.DoRequest(ctx, query, nil)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get account, got error: %s", err))
@@ -222,8 +349,20 @@ func (r *GoogleWorkspaceResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	// check if there was a change in display_name
-	if data.DisplayName != state.DisplayName || data.ClientEmail != state.ClientEmail || data.PrivateKey != state.PrivateKey {
+	if data.DisplayName != state.DisplayName || data.ClientEmail != state.ClientEmail || data.PrivateKey != state.PrivateKey ||
+		data.PrivateKeyWOVersion != state.PrivateKeyWOVersion || data.CredentialsFile != state.CredentialsFile || data.CredentialsBase64 != state.CredentialsBase64 || data.CredentialsJSON != state.CredentialsJSON {
+		key, diags := r.credentials(ctx, req.Config, data)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.ClientEmail = types.StringValue(key.ClientEmail)
+
 		query := `
 			mutation UpdateAccount($id: ID!, $account: AccountUpdateInput) {
 				updateAccount(id: $id, account: $account) {
@@ -235,13 +374,11 @@ func (r *GoogleWorkspaceResource) Update(ctx context.Context, req resource.Updat
 			"id": data.ID.ValueString(),
 			"account": map[string]interface{}{
 				"display_name": data.DisplayName.ValueString(),
-				"client_email": data.ClientEmail.ValueString(),
-				"private_key":  data.PrivateKey.ValueString(),
+				"client_email": key.ClientEmail,
+				"private_key":  key.PrivateKey,
 			}}
 
-		tflog.Debug(ctx, fmt.Sprintf("variables: %v", variables))
-
-		_, err := r.client.DoRequest(query, variables)
+		_, err := client.DoRequest(ctx, query, variables)
 
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update account, got error: %s", err))
@@ -264,6 +401,8 @@ func (r *GoogleWorkspaceResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
+	client := workspaceClient(r.client, data.WorkspaceID)
+
 	query := `
 		mutation DeleteAccount($id: ID!) {
 			deleteAccount(id: $id)
@@ -272,7 +411,7 @@ func (r *GoogleWorkspaceResource) Delete(ctx context.Context, req resource.Delet
 	variables := map[string]interface{}{
 		"id": data.ID.ValueString()}
 
-	_, err := r.client.DoRequest(query, variables)
+	_, err := client.DoRequest(ctx, query, variables)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete account, got error: %s", err))
@@ -280,6 +419,27 @@ func (r *GoogleWorkspaceResource) Delete(ctx context.Context, req resource.Delet
 	}
 }
 
+// credentials resolves the service account key from the configured credential source.
+func (r *GoogleWorkspaceResource) credentials(ctx context.Context, config tfsdk.Config, data GoogleWorkspaceResourceModel) (*utils.GCPServiceAccountKey, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var privateKeyWO types.String
+
+	diags.Append(config.GetAttribute(ctx, path.Root("private_key_wo"), &privateKeyWO)...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	key, err := utils.ResolveGCPCredentials(data.ClientEmail, data.PrivateKey, privateKeyWO, data.CredentialsJSON, data.CredentialsFile, data.CredentialsBase64)
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("private_key"), "Invalid Service Account Credentials", fmt.Sprintf("Unable to resolve the service account credentials, got error: %s", err))
+		return nil, diags
+	}
+
+	return key, diags
+}
+
 func (r *GoogleWorkspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("customer_id"), req, resp)
 }
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &IsAWSAccountIDFunction{}
+
+func NewIsAWSAccountIDFunction() function.Function {
+	return &IsAWSAccountIDFunction{}
+}
+
+type IsAWSAccountIDFunction struct{}
+
+func (f *IsAWSAccountIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_aws_account_id"
+}
+
+func (f *IsAWSAccountIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Check whether a string is an AWS account ID",
+		MarkdownDescription: "Returns `true` when the value is a 12-digit AWS account ID, the format accepted by `cloud_account_id` on the AWS resources.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "The value to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsAWSAccountIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value))
+
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, utils.IsAWSAccountID(value)))
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &NormalizeRegionsFunction{}
+
+func NewNormalizeRegionsFunction() function.Function {
+	return &NormalizeRegionsFunction{}
+}
+
+type NormalizeRegionsFunction struct{}
+
+func (f *NormalizeRegionsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_regions"
+}
+
+func (f *NormalizeRegionsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalize a list of AWS regions",
+		MarkdownDescription: "Trims, lowercases, de-duplicates and sorts a list of AWS region names so it can be passed to `cloud_regions` without causing spurious diffs. Fails if any element is not a valid region name.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				ElementType:         types.StringType,
+				Name:                "regions",
+				MarkdownDescription: "The AWS region names.",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *NormalizeRegionsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var regions []string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &regions))
+
+	if resp.Error != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	result := []string{}
+
+	for _, region := range regions {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if !utils.IsAWSRegion(region) {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q is not a valid AWS region name.", region))
+			return
+		}
+		if seen[region] {
+			continue
+		}
+		seen[region] = true
+		result = append(result, region)
+	}
+
+	sort.Strings(result)
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
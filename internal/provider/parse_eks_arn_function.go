@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseEKSARNFunction{}
+
+func NewParseEKSARNFunction() function.Function {
+	return &ParseEKSARNFunction{}
+}
+
+type ParseEKSARNFunction struct{}
+
+type ParseEKSARNFunctionModel struct {
+	Partition   types.String `tfsdk:"partition"`
+	Region      types.String `tfsdk:"region"`
+	AccountID   types.String `tfsdk:"account_id"`
+	ClusterName types.String `tfsdk:"cluster_name"`
+}
+
+func (f *ParseEKSARNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_eks_arn"
+}
+
+func (f *ParseEKSARNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parse an EKS cluster ARN",
+		MarkdownDescription: "Splits an EKS cluster ARN, as used by `streamsec_aws_kubernetes_cluster.arn`, into its partition, region, account ID and cluster name.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "arn",
+				MarkdownDescription: "The EKS cluster ARN.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"partition":    types.StringType,
+				"region":       types.StringType,
+				"account_id":   types.StringType,
+				"cluster_name": types.StringType,
+			},
+		},
+	}
+}
+
+func (f *ParseEKSARNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arn string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &arn))
+
+	if resp.Error != nil {
+		return
+	}
+
+	matches := utils.EKSClusterARNRegex.FindStringSubmatch(arn)
+
+	if matches == nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q is not a valid EKS cluster ARN, expected arn:<partition>:eks:<region>:<account_id>:cluster/<name>.", arn))
+		return
+	}
+
+	data := ParseEKSARNFunctionModel{
+		Partition:   types.StringValue(matches[1]),
+		Region:      types.StringValue(matches[2]),
+		AccountID:   types.StringValue(matches[3]),
+		ClusterName: types.StringValue(matches[4]),
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, &data))
+}
@@ -5,21 +5,32 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"strconv"
 	"terraform-provider-streamsec/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure StreamsecProvider satisfies various provider interfaces.
 var _ provider.Provider = &StreamsecProvider{}
 var _ provider.ProviderWithFunctions = &StreamsecProvider{}
+var _ provider.ProviderWithEphemeralResources = &StreamsecProvider{}
+var _ provider.ProviderWithConfigValidators = &StreamsecProvider{}
 
 // StreamsecProvider defines the provider implementation.
 type StreamsecProvider struct {
@@ -29,13 +40,30 @@ type StreamsecProvider struct {
 	version string
 }
 
+// Default client side limits, below the API throttling thresholds.
+const (
+	defaultRequestsPerSecond     = 10.0
+	defaultMaxConcurrentRequests = 5
+)
+
 // StreamsecProviderModel describes the provider data model.
 type StreamsecProviderModel struct {
-	Host        types.String `tfsdk:"host"`
-	ApiToken    types.String `tfsdk:"api_token"`
-	Username    types.String `tfsdk:"username"`
-	Password    types.String `tfsdk:"password"`
-	WorkspaceId types.String `tfsdk:"workspace_id"`
+	Host                      types.String  `tfsdk:"host"`
+	ApiToken                  types.String  `tfsdk:"api_token"`
+	Username                  types.String  `tfsdk:"username"`
+	Password                  types.String  `tfsdk:"password"`
+	WorkspaceId               types.String  `tfsdk:"workspace_id"`
+	Profile                   types.String  `tfsdk:"profile"`
+	CredentialsFile           types.String  `tfsdk:"credentials_file"`
+	UseOIDC                   types.Bool    `tfsdk:"use_oidc"`
+	OIDCToken                 types.String  `tfsdk:"oidc_token"`
+	OIDCTokenFilePath         types.String  `tfsdk:"oidc_token_file_path"`
+	OIDCRequestURL            types.String  `tfsdk:"oidc_request_url"`
+	OIDCRequestToken          types.String  `tfsdk:"oidc_request_token"`
+	OIDCAudience              types.String  `tfsdk:"oidc_audience"`
+	RequestsPerSecond         types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests     types.Int64   `tfsdk:"max_concurrent_requests"`
+	SkipCredentialsValidation types.Bool    `tfsdk:"skip_credentials_validation"`
 }
 
 func (p *StreamsecProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -45,6 +73,9 @@ func (p *StreamsecProvider) Metadata(ctx context.Context, req provider.MetadataR
 
 func (p *StreamsecProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: "Each setting is resolved in the following order: the provider configuration, then the `STREAMSEC_*` " +
+			"environment variables, then the selected profile of the credentials file. When both an API token and a username/password " +
+			"are set, the one from the higher precedence source is used.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional: true,
@@ -63,10 +94,113 @@ func (p *StreamsecProvider) Schema(ctx context.Context, req provider.SchemaReque
 			"workspace_id": schema.StringAttribute{
 				Optional: true,
 			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "The profile of the credentials file to read settings from. Can also be set with the `STREAMSEC_PROFILE` " +
+					"environment variable. When not set, the `default` profile is used if present.",
+				Optional: true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the INI credentials file holding the profiles. Can also be set with the `STREAMSEC_CREDENTIALS_FILE` " +
+					"environment variable. Defaults to `~/.streamsec/credentials`.",
+				Optional: true,
+			},
+			"use_oidc": schema.BoolAttribute{
+				MarkdownDescription: "Exchange an OIDC ID token from the CI system for a Stream.Security access token instead of using " +
+					"an API token or username/password. Can also be set with the `STREAMSEC_USE_OIDC` environment variable.",
+				Optional: true,
+			},
+			"oidc_token": schema.StringAttribute{
+				MarkdownDescription: "The OIDC ID token to exchange. Can also be set with the `STREAMSEC_OIDC_TOKEN` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oidc_token_file_path": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the OIDC ID token, read again on every refresh. Can also be set with the " +
+					"`STREAMSEC_OIDC_TOKEN_FILE_PATH` environment variable.",
+				Optional: true,
+			},
+			"oidc_request_url": schema.StringAttribute{
+				MarkdownDescription: "The URL to request the OIDC ID token from. Defaults to the `ACTIONS_ID_TOKEN_REQUEST_URL` " +
+					"environment variable set by GitHub Actions.",
+				Optional: true,
+			},
+			"oidc_request_token": schema.StringAttribute{
+				MarkdownDescription: "The bearer token for `oidc_request_url`. Defaults to the `ACTIONS_ID_TOKEN_REQUEST_TOKEN` " +
+					"environment variable set by GitHub Actions.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"oidc_audience": schema.StringAttribute{
+				MarkdownDescription: "The audience requested for the OIDC ID token. Can also be set with the `STREAMSEC_OIDC_AUDIENCE` " +
+					"environment variable. Defaults to the host.",
+				Optional: true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("The maximum rate of requests sent to the Stream.Security API, shared by all resources. "+
+					"Defaults to `%v`, `0` disables the limit.", defaultRequestsPerSecond),
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				MarkdownDescription: "Skip logging in when the provider is configured, so plans that do not refresh can run without network " +
+					"access. The provider then logs in on its first request, and runbooks are not checked against the catalog at plan time. " +
+					"Can also be set with the " +
+					"`STREAMSEC_SKIP_CREDENTIALS_VALIDATION` environment variable.",
+				Optional: true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The maximum number of requests in flight to the Stream.Security API, shared by all resources. "+
+					"Defaults to `%d`, `0` disables the limit.", defaultMaxConcurrentRequests),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 		},
 	}
 }
 
+// ConfigValidators catches partial and conflicting auth modes in the configuration: an API token,
+// a username and password, or an OIDC token source. Whether any mode is set at all can only be
+// checked in Configure, once environment variables and the profile are resolved.
+func (p *StreamsecProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.RequiredTogether(
+			path.MatchRoot("username"),
+			path.MatchRoot("password"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("api_token"),
+			path.MatchRoot("username"),
+			path.MatchRoot("oidc_token"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("api_token"),
+			path.MatchRoot("username"),
+			path.MatchRoot("oidc_token_file_path"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("api_token"),
+			path.MatchRoot("username"),
+			path.MatchRoot("oidc_request_url"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("api_token"),
+			path.MatchRoot("password"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("oidc_token"),
+			path.MatchRoot("oidc_token_file_path"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("oidc_request_url"),
+			path.MatchRoot("oidc_request_token"),
+		),
+	}
+}
+
 func (p *StreamsecProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	// Retrieve provider data from configuration
 	var config StreamsecProviderModel
@@ -117,32 +251,158 @@ func (p *StreamsecProvider) Configure(ctx context.Context, req provider.Configur
 				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_WORKSPACE_ID environment variable.",
 		)
 	}
+	if config.Profile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown Stream.Security Profile",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security profile. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_PROFILE environment variable.",
+		)
+	}
+	if config.CredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credentials_file"),
+			"Unknown Stream.Security Credentials File",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security credentials file. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_CREDENTIALS_FILE environment variable.",
+		)
+	}
+	if config.UseOIDC.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("use_oidc"),
+			"Unknown Stream.Security Use OIDC",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security use OIDC setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_USE_OIDC environment variable.",
+		)
+	}
+	if config.OIDCToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc_token"),
+			"Unknown Stream.Security OIDC Token",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security OIDC token. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_OIDC_TOKEN environment variable.",
+		)
+	}
+	if config.OIDCTokenFilePath.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc_token_file_path"),
+			"Unknown Stream.Security OIDC Token File Path",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security OIDC token file path. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_OIDC_TOKEN_FILE_PATH environment variable.",
+		)
+	}
+	if config.OIDCRequestURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc_request_url"),
+			"Unknown Stream.Security OIDC Request URL",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security OIDC request URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ACTIONS_ID_TOKEN_REQUEST_URL environment variable.",
+		)
+	}
+	if config.OIDCRequestToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc_request_token"),
+			"Unknown Stream.Security OIDC Request Token",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security OIDC request token. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the ACTIONS_ID_TOKEN_REQUEST_TOKEN environment variable.",
+		)
+	}
+	if config.SkipCredentialsValidation.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("skip_credentials_validation"),
+			"Unknown Stream.Security Skip Credentials Validation",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security skip credentials validation setting. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_SKIP_CREDENTIALS_VALIDATION environment variable.",
+		)
+	}
+	if config.OIDCAudience.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("oidc_audience"),
+			"Unknown Stream.Security OIDC Audience",
+			"The provider cannot create the Stream.Security API client as there is an unknown configuration value for the Stream.Security OIDC audience. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the STREAMSEC_OIDC_AUDIENCE environment variable.",
+		)
+	}
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
-	host := os.Getenv("STREAMSEC_HOST")
-	apiToken := os.Getenv("STREAMSEC_API_TOKEN")
-	username := os.Getenv("STREAMSEC_USERNAME")
-	password := os.Getenv("STREAMSEC_PASSWORD")
-	workspaceId := os.Getenv("STREAMSEC_WORKSPACE_ID")
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	// Start from the credentials file profile, override with environment
+	// variables and then with Terraform configuration values if set.
+	var host, workspaceId string
+	var profileCredentials authCredentials
+
+	profileName := os.Getenv("STREAMSEC_PROFILE")
+	credentialsFile := os.Getenv("STREAMSEC_CREDENTIALS_FILE")
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
+	}
+	if !config.CredentialsFile.IsNull() {
+		credentialsFile = config.CredentialsFile.ValueString()
+	}
+	if credentialsFile == "" {
+		credentialsFile = defaultCredentialsFile()
+	}
+
+	var profile *credentialsProfile
+	if profileName != "" {
+		var err error
+		profile, err = loadCredentialsProfile(credentialsFile, profileName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Unable to Load Stream.Security Profile",
+				"The provider cannot read the selected profile from the Stream.Security credentials file. "+
+					"Check the profile name and the credentials_file value or the STREAMSEC_CREDENTIALS_FILE environment variable.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return
+		}
+	} else {
+		// The default profile is optional, but a file that cannot be parsed is still reported
+		found, err := loadCredentialsProfile(credentialsFile, defaultProfile)
+		switch {
+		case err == nil:
+			profile = found
+			profileName = defaultProfile
+		case !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, errProfileNotFound):
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credentials_file"),
+				"Unable to Read Stream.Security Credentials File",
+				"The provider cannot read the Stream.Security credentials file. "+
+					"Fix the file or point credentials_file or the STREAMSEC_CREDENTIALS_FILE environment variable to another one.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return
+		}
 	}
-	if !config.ApiToken.IsNull() {
-		apiToken = config.ApiToken.ValueString()
+	if profile != nil {
+		host, workspaceId = profile.Host, profile.WorkspaceId
+		profileCredentials = authCredentials{
+			source:   fmt.Sprintf("profile %q of the credentials file", profileName),
+			apiToken: profile.ApiToken,
+			username: profile.Username,
+			password: profile.Password,
+		}
 	}
-	if !config.Username.IsNull() {
-		username = config.Username.ValueString()
+
+	if v := os.Getenv("STREAMSEC_HOST"); v != "" {
+		host = v
 	}
-	if !config.Password.IsNull() {
-		password = config.Password.ValueString()
+	if v := os.Getenv("STREAMSEC_WORKSPACE_ID"); v != "" {
+		workspaceId = v
+	}
+	if !config.Host.IsNull() {
+		host = config.Host.ValueString()
 	}
 	if !config.WorkspaceId.IsNull() {
 		workspaceId = config.WorkspaceId.ValueString()
 	}
 
+	useOIDC, _ := strconv.ParseBool(os.Getenv("STREAMSEC_USE_OIDC"))
+	if !config.UseOIDC.IsNull() {
+		useOIDC = config.UseOIDC.ValueBool()
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 	if host == "" {
@@ -154,47 +414,63 @@ func (p *StreamsecProvider) Configure(ctx context.Context, req provider.Configur
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
-	if apiToken == "" && username == "" && password == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("api_token"),
-			"Missing Stream.Security API Token or Username/Password",
-			"The provider cannot create the Stream.Security API client as there is a missing or empty value for the Stream.Security API token or username/password. "+
-				"Set the api_token value in the configuration or use the STREAMSEC_API_TOKEN environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+
+	creds, mode := resolveAuth([]authCredentials{
+		{
+			source:   "configuration",
+			apiToken: config.ApiToken.ValueString(),
+			username: config.Username.ValueString(),
+			password: config.Password.ValueString(),
+		},
+		{
+			source:   "STREAMSEC_* environment",
+			apiToken: os.Getenv("STREAMSEC_API_TOKEN"),
+			username: os.Getenv("STREAMSEC_USERNAME"),
+			password: os.Getenv("STREAMSEC_PASSWORD"),
+		},
+		profileCredentials,
+	}, useOIDC, workspaceId, &resp.Diagnostics)
+
+	var oidc *client.OIDCConfig
+	if mode == authModeOIDC {
+		oidc = &client.OIDCConfig{
+			Token:         stringValueOrEnv(config.OIDCToken, "STREAMSEC_OIDC_TOKEN"),
+			TokenFilePath: stringValueOrEnv(config.OIDCTokenFilePath, "STREAMSEC_OIDC_TOKEN_FILE_PATH"),
+			RequestURL:    stringValueOrEnv(config.OIDCRequestURL, "ACTIONS_ID_TOKEN_REQUEST_URL"),
+			RequestToken:  stringValueOrEnv(config.OIDCRequestToken, "ACTIONS_ID_TOKEN_REQUEST_TOKEN"),
+			Audience:      stringValueOrEnv(config.OIDCAudience, "STREAMSEC_OIDC_AUDIENCE"),
+		}
+		if oidc.Audience == "" {
+			oidc.Audience = host
+		}
+		if oidc.Token == "" && oidc.TokenFilePath == "" && (oidc.RequestURL == "" || oidc.RequestToken == "") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("use_oidc"),
+				"Missing Stream.Security OIDC Token Source",
+				"The provider cannot create the Stream.Security API client as use_oidc is set but there is no OIDC token source. "+
+					"Set oidc_token, oidc_token_file_path, or oidc_request_url and oidc_request_token, or run in a GitHub Actions job "+
+					"with the id-token: write permission.",
+			)
+		}
 	}
-	if username == "" && apiToken == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Missing Stream.Security API Username or API Token",
-			"The provider cannot create the Stream.Security API client as there is a missing or empty value for the Stream.Security API username. "+
-				"Set the username value in the configuration or use the STREAMSEC_USERNAME environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if password == "" && apiToken == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("password"),
-			"Missing Stream.Security API Password or API Token",
-			"The provider cannot create the Stream.Security API client as there is a missing or empty value for the Stream.Security API password. "+
-				"Set the password value in the configuration or use the STREAMSEC_PASSWORD environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+
+	apiToken, username, password := creds.apiToken, creds.username, creds.password
+
+	limits := client.Limits{
+		RequestsPerSecond:     defaultRequestsPerSecond,
+		MaxConcurrentRequests: defaultMaxConcurrentRequests,
 	}
-	if workspaceId == "" && apiToken == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("workspace_id"),
-			"Missing Stream.Security API Workspace ID or API Token",
-			"The provider cannot create the Stream.Security API client as there is a missing or empty value for the Stream.Security API workspace ID. "+
-				"Set the workspace_id value in the configuration or use the STREAMSEC_WORKSPACE_ID environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() {
+		limits.RequestsPerSecond = config.RequestsPerSecond.ValueFloat64()
 	}
-	if resp.Diagnostics.HasError() {
-		return
+	if !config.MaxConcurrentRequests.IsNull() && !config.MaxConcurrentRequests.IsUnknown() {
+		limits.MaxConcurrentRequests = config.MaxConcurrentRequests.ValueInt64()
 	}
 	// Create a new Stream.Security client using the configuration values
-	client, err := client.NewClient(&host, &username, &password, &workspaceId, &apiToken)
+	client, err := client.NewClient(&host, &username, &password, &workspaceId, &apiToken, oidc, limits, fmt.Sprintf("terraform-provider-streamsec/%s terraform/%s", p.version, req.TerraformVersion))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Stream.Security API Client",
@@ -204,10 +480,38 @@ func (p *StreamsecProvider) Configure(ctx context.Context, req provider.Configur
 		)
 		return
 	}
+
+	skipCredentialsValidation, _ := strconv.ParseBool(os.Getenv("STREAMSEC_SKIP_CREDENTIALS_VALIDATION"))
+	if !config.SkipCredentialsValidation.IsNull() {
+		skipCredentialsValidation = config.SkipCredentialsValidation.ValueBool()
+	}
+
+	client.SkipCredentialsValidation = skipCredentialsValidation
+
+	if !skipCredentialsValidation {
+		if err := client.Authenticate(ctx); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Authenticate to Stream.Security API",
+				"The provider could not log in with the configured credentials. "+
+					"Set skip_credentials_validation to defer the login to the first API request.\n\n"+
+					"Stream.Security Client Error: "+err.Error(),
+			)
+			return
+		}
+	}
 	// Make the Stream.Security client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
+}
+
+// stringValueOrEnv returns the configured value, falling back to the environment variable.
+func stringValueOrEnv(value types.String, env string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	return os.Getenv(env)
 }
 
 func (p *StreamsecProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -216,6 +520,7 @@ func (p *StreamsecProvider) Resources(ctx context.Context) []func() resource.Res
 		NewAWSKubernetesClusterResource,
 		NewAWSAccountAckResource,
 		NewAWSRealTimeEventsAckResource,
+		NewAWSRealTimeEventsResource,
 		NewAWSCostAckResource,
 		NewAzureTenantResource,
 		NewAzureTenantAckResource,
@@ -223,7 +528,9 @@ func (p *StreamsecProvider) Resources(ctx context.Context) []func() resource.Res
 		NewGCPProjectAckResource,
 		NewGoogleWorkspaceResource,
 		NewAWSResponseAckResource,
+		NewAWSRemediationRunbookResource,
 		NewGCPResponseAckResource,
+		NewWorkspaceResource,
 	}
 }
 
@@ -233,11 +540,26 @@ func (p *StreamsecProvider) DataSources(ctx context.Context) []func() datasource
 		NewAWSAccountDataSource,
 		NewAzureTenantDataSource,
 		NewGCPProjectDataSource,
+		NewAWSIAMRolePolicyDataSource,
+		NewAWSTrustPolicyDataSource,
+		NewAWSOnboardingTemplateDataSource,
+		NewRunbooksDataSource,
+	}
+}
+
+func (p *StreamsecProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewCollectionTokenEphemeralResource,
 	}
 }
 
 func (p *StreamsecProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewParseEKSARNFunction,
+		NewIsAWSAccountIDFunction,
+		NewNormalizeRegionsFunction,
+		NewTemplateURLFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProviderConfigValidators(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  map[string]tftypes.Value
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			config: map[string]tftypes.Value{},
+		},
+		{
+			name:   "api token",
+			config: map[string]tftypes.Value{"api_token": tftypes.NewValue(tftypes.String, "token")},
+		},
+		{
+			name: "username and password",
+			config: map[string]tftypes.Value{
+				"username": tftypes.NewValue(tftypes.String, "user"),
+				"password": tftypes.NewValue(tftypes.String, "pass"),
+			},
+		},
+		{
+			name:    "username without password",
+			config:  map[string]tftypes.Value{"username": tftypes.NewValue(tftypes.String, "user")},
+			wantErr: true,
+		},
+		{
+			name:    "password without username",
+			config:  map[string]tftypes.Value{"password": tftypes.NewValue(tftypes.String, "pass")},
+			wantErr: true,
+		},
+		{
+			name: "api token and username",
+			config: map[string]tftypes.Value{
+				"api_token": tftypes.NewValue(tftypes.String, "token"),
+				"username":  tftypes.NewValue(tftypes.String, "user"),
+				"password":  tftypes.NewValue(tftypes.String, "pass"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "api token and oidc token",
+			config: map[string]tftypes.Value{
+				"api_token":  tftypes.NewValue(tftypes.String, "token"),
+				"oidc_token": tftypes.NewValue(tftypes.String, "oidc"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "username and oidc request url",
+			config: map[string]tftypes.Value{
+				"username":           tftypes.NewValue(tftypes.String, "user"),
+				"password":           tftypes.NewValue(tftypes.String, "pass"),
+				"oidc_request_url":   tftypes.NewValue(tftypes.String, "https://token.actions.githubusercontent.com"),
+				"oidc_request_token": tftypes.NewValue(tftypes.String, "request-token"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "oidc request url without token",
+			config: map[string]tftypes.Value{
+				"oidc_request_url": tftypes.NewValue(tftypes.String, "https://token.actions.githubusercontent.com"),
+			},
+			wantErr: true,
+		},
+	}
+
+	ctx := context.Background()
+	p := &StreamsecProvider{}
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{}
+			for name, attributeType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attributeType, nil)
+				if value, ok := tc.config[name]; ok {
+					values[name] = value
+				}
+			}
+
+			req := provider.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+			}
+
+			var diags diag.Diagnostics
+			for _, configValidator := range p.ConfigValidators(ctx) {
+				var resp provider.ValidateConfigResponse
+				configValidator.ValidateProvider(ctx, req, &resp)
+				diags.Append(resp.Diagnostics...)
+			}
+
+			if diags.HasError() != tc.wantErr {
+				t.Errorf("ConfigValidators() errors = %v, wantErr %v", diags.Errors(), tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RunbooksDataSource{}
+
+func NewRunbooksDataSource() datasource.DataSource {
+	return &RunbooksDataSource{}
+}
+
+// RunbooksDataSource defines the data source implementation.
+type RunbooksDataSource struct {
+	client *client.Client
+}
+
+// RunbooksDataSourceModel describes the data source data model.
+type RunbooksDataSourceModel struct {
+	Cloud       types.String   `tfsdk:"cloud"`
+	Runbooks    []RunbookModel `tfsdk:"runbooks"`
+	WorkspaceID types.String   `tfsdk:"workspace_id"`
+}
+
+// RunbookModel describes a runbook of the catalog.
+type RunbookModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Permissions types.List   `tfsdk:"permissions"`
+	Role        types.String `tfsdk:"role"`
+}
+
+func (d *RunbooksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runbooks"
+}
+
+func (d *RunbooksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "The remediation runbooks available for a cloud.",
+
+		Attributes: map[string]schema.Attribute{
+			"cloud": schema.StringAttribute{
+				MarkdownDescription: "The cloud of the runbooks: `aws` or `gcp`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("aws", "gcp"),
+				},
+			},
+			"runbooks": schema.ListNestedAttribute{
+				MarkdownDescription: "The runbooks.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The runbook ID, as used in `runbook_list`.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The runbook name.",
+							Computed:            true,
+						},
+						"permissions": schema.ListAttribute{
+							MarkdownDescription: "The permissions the runbook needs.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"role": schema.StringAttribute{
+							MarkdownDescription: "The role the runbook runs with, as used in `runbook_role_list`.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The workspace ID to read from, overrides the provider workspace_id.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (d *RunbooksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RunbooksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RunbooksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := workspaceClient(d.client, data.WorkspaceID)
+
+	runbooks, err := getRunbooks(ctx, client, data.Cloud.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get runbooks, got error: %s", err))
+		return
+	}
+
+	data.Runbooks = []RunbookModel{}
+	for _, runbook := range runbooks {
+		data.Runbooks = append(data.Runbooks, RunbookModel{
+			ID:          utils.ConvertInterfaceToTypesString(runbook["id"]),
+			Name:        utils.ConvertInterfaceToTypesString(runbook["name"]),
+			Permissions: utils.ConvertOptionalInterfaceToTypesList(runbook["permissions"]),
+			Role:        utils.ConvertInterfaceToOptionalTypesString(runbook["role"]),
+		})
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// getRunbooks returns the runbook catalog of the cloud.
+func getRunbooks(ctx context.Context, client *client.Client, cloud string) ([]map[string]interface{}, error) {
+	query := `
+		query runbooks($cloud: String!) {
+			runbooks(cloud_provider: $cloud) {
+				id
+				name
+				permissions
+				role
+			}
+		}`
+
+	res, err := client.DoRequest(ctx, query, map[string]interface{}{"cloud": cloud})
+
+	if err != nil {
+		return nil, err
+	}
+
+	runbooks := []map[string]interface{}{}
+	for _, runbook := range res["runbooks"].([]interface{}) {
+		runbooks = append(runbooks, runbook.(map[string]interface{}))
+	}
+
+	return runbooks, nil
+}
+
+// runbookCatalogClient returns the client to check planned runbooks against the catalog with, or
+// nil with a warning when the plan must not depend on the API: the provider skips the credentials
+// validation, or the client cannot authenticate.
+func runbookCatalogClient(ctx context.Context, c *client.Client, workspaceID types.String) (*client.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if c.SkipCredentialsValidation {
+		diags.AddWarning("Runbooks Not Validated",
+			"The runbooks were not checked against the Stream.Security runbook catalog as skip_credentials_validation is set.")
+		return nil, diags
+	}
+
+	wc := workspaceClient(c, workspaceID)
+
+	if err := wc.Authenticate(ctx); err != nil {
+		diags.AddWarning("Runbooks Not Validated",
+			fmt.Sprintf("The runbooks were not checked against the Stream.Security runbook catalog as the provider cannot authenticate, got error: %s", err))
+		return nil, diags
+	}
+
+	return wc, diags
+}
+
+// plannedRunbook is a runbook ID of a plan and the attribute path to report it at.
+type plannedRunbook struct {
+	id   string
+	path path.Path
+}
+
+// listRunbooks returns the known runbooks of a runbook list attribute at p.
+func listRunbooks(runbookList types.List, p path.Path) []plannedRunbook {
+	if runbookList.IsUnknown() || runbookList.IsNull() {
+		return nil
+	}
+
+	runbooks := []plannedRunbook{}
+	for i, value := range runbookList.Elements() {
+		if id, ok := value.(types.String); ok && !id.IsUnknown() && !id.IsNull() {
+			runbooks = append(runbooks, plannedRunbook{id: id.ValueString(), path: p.AtListIndex(i)})
+		}
+	}
+	return runbooks
+}
+
+// validateRunbooks checks at plan time that the runbooks exist in the catalog of the cloud and,
+// when roles is not null, that the role of each runbook is listed in roles.
+func validateRunbooks(ctx context.Context, client *client.Client, cloud string, runbooks []plannedRunbook, roles types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(runbooks) == 0 || roles.IsUnknown() {
+		return diags
+	}
+
+	catalog, err := getRunbooks(ctx, client, cloud)
+
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get runbooks, got error: %s", err))
+		return diags
+	}
+
+	catalogRunbooks := map[string]map[string]interface{}{}
+	for _, runbook := range catalog {
+		if id, ok := runbook["id"].(string); ok {
+			catalogRunbooks[id] = runbook
+		}
+	}
+
+	roleSet := map[string]bool{}
+	for _, role := range knownStrings(roles.Elements()) {
+		roleSet[role] = true
+	}
+
+	for _, planned := range runbooks {
+		runbook, found := catalogRunbooks[planned.id]
+		if !found {
+			diags.AddAttributeError(
+				planned.path,
+				"Unknown Runbook",
+				fmt.Sprintf("Runbook %s does not exist for %s, see the streamsec_runbooks data source for the available runbooks.", planned.id, cloud),
+			)
+			continue
+		}
+
+		if role, _ := runbook["role"].(string); !roles.IsNull() && role != "" && !roleSet[role] {
+			diags.AddAttributeError(
+				path.Root("runbook_role_list"),
+				"Missing Runbook Role",
+				fmt.Sprintf("Runbook %s runs with role %s, which is missing from runbook_role_list.", planned.id, role),
+			)
+		}
+	}
+
+	return diags
+}
+
+// knownStrings returns the known string elements of values.
+func knownStrings(values []attr.Value) []string {
+	result := []string{}
+	for _, value := range values {
+		if s, ok := value.(types.String); ok && !s.IsUnknown() && !s.IsNull() {
+			result = append(result, s.ValueString())
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &TemplateURLFunction{}
+
+func NewTemplateURLFunction() function.Function {
+	return &TemplateURLFunction{}
+}
+
+type TemplateURLFunction struct{}
+
+func (f *TemplateURLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "template_url"
+}
+
+func (f *TemplateURLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the CloudFormation quick-create URL for AWS onboarding",
+		MarkdownDescription: "Returns the AWS console quick-create URL for a Stream.Security onboarding stack in the given account and region, " +
+			"with the external ID pre-filled. Functions cannot call the Stream.Security API, pass the template location it returns, " +
+			"e.g. the `template_url` of the `streamsec_aws_onboarding_template` data source.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "account_id",
+				MarkdownDescription: "The 12-digit AWS account ID.",
+			},
+			function.StringParameter{
+				Name:                "region",
+				MarkdownDescription: "The region the stack is deployed in.",
+			},
+			function.StringParameter{
+				Name:                "external_id",
+				MarkdownDescription: "The external ID of the `streamsec_aws_account`.",
+			},
+			function.StringParameter{
+				Name:                "template_location",
+				MarkdownDescription: "The HTTPS location of the template, as returned by the Stream.Security API.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *TemplateURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var accountID, region, externalID, templateLocation string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &accountID, &region, &externalID, &templateLocation))
+
+	if resp.Error != nil {
+		return
+	}
+
+	templateURL, err := quickCreateURL(accountID, region, externalID, templateLocation)
+
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, err)
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, templateURL))
+}
+
+// quickCreateURL builds the CloudFormation console quick-create link of the template, the
+// counterpart of quickCreateTemplateURL.
+func quickCreateURL(accountID, region, externalID, templateLocation string) (string, *function.FuncError) {
+	var funcErr *function.FuncError
+
+	if !utils.IsAWSAccountID(accountID) {
+		funcErr = function.ConcatFuncErrors(funcErr, function.NewArgumentFuncError(0, "The account ID must be a 12-digit number."))
+	}
+	if !utils.IsAWSRegion(region) {
+		funcErr = function.ConcatFuncErrors(funcErr, function.NewArgumentFuncError(1, fmt.Sprintf("%q is not a valid AWS region name.", region)))
+	}
+	if externalID == "" {
+		funcErr = function.ConcatFuncErrors(funcErr, function.NewArgumentFuncError(2, "The external ID must not be empty."))
+	}
+	if location, err := url.Parse(templateLocation); err != nil || location.Scheme != "https" || location.Host == "" {
+		funcErr = function.ConcatFuncErrors(funcErr, function.NewArgumentFuncError(3, fmt.Sprintf("%q is not an HTTPS template location.", templateLocation)))
+	}
+	if funcErr != nil {
+		return "", funcErr
+	}
+
+	params := url.Values{}
+	params.Set("templateURL", templateLocation)
+	params.Set("stackName", "StreamSecurity")
+	params.Set("param_AccountID", accountID)
+	params.Set("param_ExternalID", externalID)
+
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/cloudformation/home?region=%s#/stacks/quickcreate?%s", region, region, params.Encode()), nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import "testing"
+
+func TestQuickCreateURL(t *testing.T) {
+	cases := []struct {
+		name             string
+		accountID        string
+		region           string
+		externalID       string
+		templateLocation string
+		wantErr          bool
+	}{
+		{
+			name:             "valid",
+			accountID:        "123456789011",
+			region:           "eu-west-1",
+			externalID:       "external-id",
+			templateLocation: "https://bucket.s3.amazonaws.com/template.yaml",
+		},
+		{
+			name:             "invalid account",
+			accountID:        "1234",
+			region:           "eu-west-1",
+			externalID:       "external-id",
+			templateLocation: "https://bucket.s3.amazonaws.com/template.yaml",
+			wantErr:          true,
+		},
+		{
+			name:             "missing external id",
+			accountID:        "123456789011",
+			region:           "eu-west-1",
+			templateLocation: "https://bucket.s3.amazonaws.com/template.yaml",
+			wantErr:          true,
+		},
+		{
+			name:             "template location not https",
+			accountID:        "123456789011",
+			region:           "eu-west-1",
+			externalID:       "external-id",
+			templateLocation: "s3://bucket/template.yaml",
+			wantErr:          true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, funcErr := quickCreateURL(tc.accountID, tc.region, tc.externalID, tc.templateLocation)
+			if (funcErr != nil) != tc.wantErr {
+				t.Fatalf("quickCreateURL() error = %v, wantErr %v", funcErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			// The template location must round-trip through the link
+			location, err := quickCreateTemplateURL(got)
+			if err != nil {
+				t.Fatalf("quickCreateTemplateURL(%q) error = %v", got, err)
+			}
+			if location != tc.templateLocation {
+				t.Errorf("quickCreateTemplateURL(%q) = %q, want %q", got, location, tc.templateLocation)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package provider
+
+import (
+	"terraform-provider-streamsec/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// workspaceClient returns the client for the workspace_id override of a resource or data source,
+// falling back to the provider client when it is not set.
+func workspaceClient(c *client.Client, workspaceID types.String) *client.Client {
+	if workspaceID.IsNull() || workspaceID.IsUnknown() {
+		return c
+	}
+
+	return c.ForWorkspace(workspaceID.ValueString())
+}
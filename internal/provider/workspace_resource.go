@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-streamsec/internal/client"
+	"terraform-provider-streamsec/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &WorkspaceResource{}
+var _ resource.ResourceWithImportState = &WorkspaceResource{}
+
+func NewWorkspaceResource() resource.Resource {
+	return &WorkspaceResource{}
+}
+
+type WorkspaceResource struct {
+	client *client.Client
+}
+type WorkspaceResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Name              types.String `tfsdk:"name"`
+	Region            types.String `tfsdk:"region"`
+	DataRetentionDays types.Int64  `tfsdk:"data_retention_days"`
+	DefaultFrameworks types.List   `tfsdk:"default_frameworks"`
+}
+
+func (r *WorkspaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+func (r *WorkspaceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Workspace resource. The `id` can be passed as `workspace_id` to other resources or provider blocks.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the workspace.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the workspace.",
+				Required:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the workspace data is hosted in.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"data_retention_days": schema.Int64Attribute{
+				Description: "The number of days collected data is retained.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"default_frameworks": schema.ListAttribute{
+				ElementType: types.StringType,
+				Description: "The compliance frameworks enabled by default for accounts in the workspace.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *WorkspaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*client.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *WorkspaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data WorkspaceResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := `
+		mutation CreateWorkspace($workspace: WorkspaceInput!) {
+			createWorkspace(workspace: $workspace) {
+				_id
+				name
+				region
+				data_retention_days
+				default_frameworks
+			}
+		}`
+
+	variables := map[string]interface{}{
+		"workspace": r.workspaceInput(data),
+	}
+
+	res, err := r.client.DoRequest(ctx, query, variables)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create workspace, got error: %s", err))
+		return
+	}
+
+	r.readWorkspace(res["createWorkspace"].(map[string]interface{}), &data)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "created a resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data WorkspaceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := `
+		query {
+			workspaces {
+				_id
+				name
+				region
+				data_retention_days
+				default_frameworks
+			}
+		}`
+
+	res, err := r.client.DoRequest(ctx, query, nil)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get workspaces, got error: %s", err))
+		return
+	}
+
+	workspaces := res["workspaces"].([]interface{})
+	workspaceFound := false
+
+	for _, item := range workspaces {
+
+		workspace := item.(map[string]interface{})
+		if workspace["_id"].(string) == data.ID.ValueString() {
+			r.readWorkspace(workspace, &data)
+			workspaceFound = true
+		}
+	}
+
+	if !workspaceFound {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data WorkspaceResourceModel
+	var state WorkspaceResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := `
+		mutation UpdateWorkspace($id: ID!, $workspace: WorkspaceUpdateInput) {
+			updateWorkspace(id: $id, workspace: $workspace) {
+				_id
+				name
+				region
+				data_retention_days
+				default_frameworks
+			}
+		}`
+
+	workspace := r.workspaceInput(data)
+	delete(workspace, "region")
+
+	variables := map[string]interface{}{
+		"id":        data.ID.ValueString(),
+		"workspace": workspace,
+	}
+
+	res, err := r.client.DoRequest(ctx, query, variables)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update workspace, got error: %s", err))
+		return
+	}
+
+	r.readWorkspace(res["updateWorkspace"].(map[string]interface{}), &data)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data WorkspaceResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	query := `
+		mutation DeleteWorkspace($id: ID!) {
+			deleteWorkspace(id: $id)
+		}`
+
+	variables := map[string]interface{}{
+		"id": data.ID.ValueString()}
+
+	_, err := r.client.DoRequest(ctx, query, variables)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete workspace, got error: %s", err))
+		return
+	}
+}
+
+func (r *WorkspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// workspaceInput builds the workspace input, leaving out settings that are not configured
+// so the platform defaults apply.
+func (r *WorkspaceResource) workspaceInput(data WorkspaceResourceModel) map[string]interface{} {
+	workspace := map[string]interface{}{
+		"name":   data.Name.ValueString(),
+		"region": data.Region.ValueString(),
+	}
+	if !data.DataRetentionDays.IsNull() && !data.DataRetentionDays.IsUnknown() {
+		workspace["data_retention_days"] = data.DataRetentionDays.ValueInt64()
+	}
+	if !data.DefaultFrameworks.IsNull() && !data.DefaultFrameworks.IsUnknown() {
+		workspace["default_frameworks"] = utils.ConvertToStringSlice(data.DefaultFrameworks.Elements())
+	}
+	return workspace
+}
+
+func (r *WorkspaceResource) readWorkspace(workspace map[string]interface{}, data *WorkspaceResourceModel) {
+	data.ID = types.StringValue(workspace["_id"].(string))
+	data.Name = types.StringValue(workspace["name"].(string))
+	data.Region = types.StringValue(workspace["region"].(string))
+	data.DataRetentionDays = types.Int64Null()
+	if days, ok := workspace["data_retention_days"].(float64); ok {
+		data.DataRetentionDays = types.Int64Value(int64(days))
+	}
+	data.DefaultFrameworks = utils.ConvertInterfaceToTypesList([]interface{}{})
+	if frameworks, ok := workspace["default_frameworks"].([]interface{}); ok {
+		data.DefaultFrameworks = utils.ConvertInterfaceToTypesList(frameworks)
+	}
+}
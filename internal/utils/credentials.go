@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// GCPServiceAccountKey holds the fields of a GCP service account JSON key used by Stream.Security.
+type GCPServiceAccountKey struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// ResolveSecret returns the secret from whichever of the inline, write-only or file sources is set.
+func ResolveSecret(value, writeOnly, file types.String) (string, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString(), nil
+	}
+	if !writeOnly.IsNull() && !writeOnly.IsUnknown() {
+		return writeOnly.ValueString(), nil
+	}
+	if !file.IsNull() && !file.IsUnknown() {
+		content, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			return "", fmt.Errorf("unable to read secret file %s: %w", file.ValueString(), err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	return "", errors.New("no secret configured")
+}
+
+// ParseGCPServiceAccountKey decodes a GCP service account JSON key and checks that it is a
+// service account key with a client email and a PEM encoded private key.
+func ParseGCPServiceAccountKey(raw []byte) (*GCPServiceAccountKey, error) {
+	var key GCPServiceAccountKey
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, fmt.Errorf("invalid service account JSON: %w", err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("expected a key of type service_account, got %q", key.Type)
+	}
+	if !strings.Contains(key.ClientEmail, "@") {
+		return nil, errors.New("service account JSON must contain a valid client_email")
+	}
+	if err := validatePrivateKey(key.PrivateKey); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func validatePrivateKey(privateKey string) error {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return errors.New("private_key is not a PEM encoded key")
+	}
+	if block.Type != "PRIVATE KEY" && block.Type != "RSA PRIVATE KEY" {
+		return fmt.Errorf("private_key must be a PRIVATE KEY block, got %s", block.Type)
+	}
+	return nil
+}
+
+// LoadGCPServiceAccountKey reads a GCP service account JSON key from an inline JSON string, a file path
+// or a base64 encoded string. It returns nil when none of them is set.
+func LoadGCPServiceAccountKey(credentialsJSON, file, encoded types.String) (*GCPServiceAccountKey, error) {
+	if !credentialsJSON.IsNull() && !credentialsJSON.IsUnknown() {
+		return ParseGCPServiceAccountKey([]byte(credentialsJSON.ValueString()))
+	}
+	if !file.IsNull() && !file.IsUnknown() {
+		content, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("unable to read credentials file %s: %w", file.ValueString(), err)
+		}
+		return ParseGCPServiceAccountKey(content)
+	}
+	if !encoded.IsNull() && !encoded.IsUnknown() {
+		content, err := base64.StdEncoding.DecodeString(encoded.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("credentials_base64 is not valid base64: %w", err)
+		}
+		return ParseGCPServiceAccountKey(content)
+	}
+	return nil, nil
+}
+
+// ResolveGCPCredentials returns the service account key from the JSON key sources when set,
+// otherwise one built from the client_email and private_key (or private_key_wo) attributes.
+func ResolveGCPCredentials(clientEmail, privateKey, privateKeyWO, credentialsJSON, file, encoded types.String) (*GCPServiceAccountKey, error) {
+	key, err := LoadGCPServiceAccountKey(credentialsJSON, file, encoded)
+	if err != nil || key != nil {
+		return key, err
+	}
+
+	secret, err := ResolveSecret(privateKey, privateKeyWO, types.StringNull())
+	if err != nil {
+		return nil, err
+	}
+	return &GCPServiceAccountKey{ClientEmail: clientEmail.ValueString(), PrivateKey: secret}, nil
+}
@@ -75,3 +75,33 @@ func ConvertToStringMap(values map[string]attr.Value) map[string]string {
 	}
 	return result
 }
+
+// ConvertInterfaceToTypesString converts an optional string field of an API response,
+// returning null when it is missing.
+func ConvertInterfaceToTypesString(value interface{}) types.String {
+	s, ok := value.(string)
+	if !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// ConvertInterfaceToOptionalTypesString converts an optional string field of an API response,
+// returning null when it is missing or empty.
+func ConvertInterfaceToOptionalTypesString(value interface{}) types.String {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// ConvertOptionalInterfaceToTypesList converts an optional string list field of an API response,
+// returning an empty list when it is missing.
+func ConvertOptionalInterfaceToTypesList(value interface{}) types.List {
+	values, ok := value.([]interface{})
+	if !ok {
+		return ConvertInterfaceToTypesList([]interface{}{})
+	}
+	return ConvertInterfaceToTypesList(values)
+}
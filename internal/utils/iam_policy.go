@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/json"
+)
+
+// IAMPolicyDocument is an AWS IAM policy document.
+type IAMPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []IAMPolicyStatement `json:"Statement"`
+}
+
+// IAMPolicyStatement is a statement of an AWS IAM policy document.
+type IAMPolicyStatement struct {
+	Sid       string                       `json:"Sid,omitempty"`
+	Effect    string                       `json:"Effect"`
+	Principal map[string]interface{}       `json:"Principal,omitempty"`
+	Action    []string                     `json:"Action"`
+	Resource  interface{}                  `json:"Resource,omitempty"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// NewIAMPolicyDocument returns a policy document with the given statements.
+func NewIAMPolicyDocument(statements ...IAMPolicyStatement) IAMPolicyDocument {
+	return IAMPolicyDocument{
+		Version:   "2012-10-17",
+		Statement: statements,
+	}
+}
+
+// JSON renders the document with a stable layout, so plans only change when the policy does.
+func (d IAMPolicyDocument) JSON() (string, error) {
+	raw, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
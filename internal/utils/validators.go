@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"regexp"
+)
+
+// Identifier formats shared by the resource schemas and the provider functions.
+var (
+	AWSAccountIDRegex  = regexp.MustCompile(`^(\d{12})$`)
+	AWSRegionRegex     = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d$`)
+	EKSClusterARNRegex = regexp.MustCompile(`^arn:(aws[a-z-]*):eks:([a-z0-9-]+):(\d{12}):cluster/([0-9A-Za-z][0-9A-Za-z_-]*)$`)
+	AzureIDRegex       = regexp.MustCompile(`^[0-9a-z-]{36}$`)
+	GCPProjectIDRegex  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{4,28}[a-z0-9]$`)
+	S3BucketARNRegex   = regexp.MustCompile(`^arn:(aws[a-z-]*):s3:::([a-z0-9][a-z0-9.-]{1,61}[a-z0-9])$`)
+	KMSKeyARNRegex     = regexp.MustCompile(`^arn:(aws[a-z-]*):kms:([a-z0-9-]+):(\d{12}):key/([0-9A-Za-z-]+)$`)
+)
+
+// IsAWSAccountID reports whether value is a 12-digit AWS account ID.
+func IsAWSAccountID(value string) bool {
+	return AWSAccountIDRegex.MatchString(value)
+}
+
+// IsAWSRegion reports whether value looks like an AWS region name, e.g. us-east-1.
+func IsAWSRegion(value string) bool {
+	return AWSRegionRegex.MatchString(value)
+}